@@ -0,0 +1,78 @@
+// internal/storage/analyses_store.go
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Analysis is one persisted analyzer run. Data holds a JSON-encoded
+// analyzer.AnalysisResult - storage does not import analyzer, so callers
+// (analyzer.Engine) are responsible for encoding/decoding it themselves.
+type Analysis struct {
+	ID          int64
+	Fingerprint string
+	Timestamp   time.Time
+	Data        string
+}
+
+// SaveAnalysis persists one analyzer run and returns its row ID. Writes
+// go straight through s.db rather than the batching write queue, same as
+// checkpoints - analysis runs are low-volume and a caller deduping
+// against GetAnalysisByFingerprint wants to see its own write
+// immediately.
+func (s *SQLiteStore) SaveAnalysis(fingerprint string, timestamp time.Time, data string) (int64, error) {
+	result, err := s.db.Exec(`
+		INSERT INTO analyses (fingerprint, timestamp, data)
+		VALUES (?, ?, ?)
+	`, fingerprint, timestamp, data)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// ListAnalyses returns every analysis run whose timestamp falls within
+// [start, end], most recent first.
+func (s *SQLiteStore) ListAnalyses(start, end time.Time) ([]*Analysis, error) {
+	rows, err := s.readDB.Query(`
+		SELECT id, fingerprint, timestamp, data
+		FROM analyses
+		WHERE timestamp BETWEEN ? AND ?
+		ORDER BY timestamp DESC
+	`, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanAnalyses(rows)
+}
+
+// GetAnalysisByFingerprint returns every run sharing fingerprint since
+// the given time, most recent first - the lookup generateRecommendations
+// uses to recognize "this is the Nth occurrence of this incident".
+func (s *SQLiteStore) GetAnalysisByFingerprint(fingerprint string, since time.Time) ([]*Analysis, error) {
+	rows, err := s.readDB.Query(`
+		SELECT id, fingerprint, timestamp, data
+		FROM analyses
+		WHERE fingerprint = ? AND timestamp >= ?
+		ORDER BY timestamp DESC
+	`, fingerprint, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanAnalyses(rows)
+}
+
+func scanAnalyses(rows *sql.Rows) ([]*Analysis, error) {
+	var analyses []*Analysis
+	for rows.Next() {
+		a := &Analysis{}
+		if err := rows.Scan(&a.ID, &a.Fingerprint, &a.Timestamp, &a.Data); err != nil {
+			return nil, err
+		}
+		analyses = append(analyses, a)
+	}
+	return analyses, rows.Err()
+}
@@ -0,0 +1,53 @@
+// internal/storage/checkpoints.go
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Checkpoint records how far a streaming collector has read into a given
+// source/key pair (e.g. source="file", key=the file's path), so a restart
+// can resume instead of re-reading or skipping data. Inode is used to
+// detect log rotation: if the file at key now has a different inode than
+// the checkpoint recorded, the old offset no longer applies and the
+// collector should start over from 0.
+type Checkpoint struct {
+	Source    string
+	Key       string
+	Offset    int64
+	Inode     int64
+	UpdatedAt time.Time
+}
+
+// GetCheckpoint returns the saved checkpoint for (source, key), or nil if
+// none has been saved yet.
+func (s *SQLiteStore) GetCheckpoint(source, key string) (*Checkpoint, error) {
+	cp := &Checkpoint{Source: source, Key: key}
+	err := s.readDB.QueryRow(`
+		SELECT offset, inode, updated_at FROM checkpoints WHERE source = ? AND key = ?
+	`, source, key).Scan(&cp.Offset, &cp.Inode, &cp.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// SaveCheckpoint upserts cp. Checkpoint writes are low-volume and a
+// collector needs them durable before it reads its next chunk, so they go
+// straight to the writer connection rather than through the batching write
+// queue used for events.
+func (s *SQLiteStore) SaveCheckpoint(cp *Checkpoint) error {
+	_, err := s.db.Exec(`
+		INSERT INTO checkpoints (source, key, offset, inode, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(source, key) DO UPDATE SET
+			offset = excluded.offset,
+			inode = excluded.inode,
+			updated_at = excluded.updated_at
+	`, cp.Source, cp.Key, cp.Offset, cp.Inode, cp.UpdatedAt)
+	return err
+}
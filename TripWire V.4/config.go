@@ -10,9 +10,24 @@ import (
 )
 
 type Config struct {
+	Storage    StorageConfig              `yaml:"storage"`
 	Collectors map[string]CollectorConfig `yaml:"collectors"`
 	Patterns   []PatternConfig            `yaml:"patterns"`
 	Thresholds ThresholdConfig            `yaml:"thresholds"`
+
+	// Dependencies declares known service dependencies as downstream ->
+	// upstream (e.g. "api" depends on "db" and "cache"). analyzer.Engine
+	// uses this to weight its causal graph: an event on a service's
+	// declared dependency is a stronger candidate root cause than mere
+	// temporal proximity alone would suggest.
+	Dependencies map[string][]string `yaml:"dependencies"`
+}
+
+// StorageConfig selects the storage.Store backend. DSN is a filesystem
+// path for the sqlite driver and a libpq connection string for postgres.
+type StorageConfig struct {
+	Driver string `yaml:"driver"`
+	DSN    string `yaml:"dsn"`
 }
 
 type CollectorConfig struct {
@@ -22,18 +37,61 @@ type CollectorConfig struct {
 	Options map[string]string `yaml:"options"`
 }
 
+// PatternConfig describes one rule for analyzer.PatternMatcher. Type
+// selects which matcher compiles it:
+//
+//	keyword  (default) - Keywords, case-insensitive substring match
+//	regex              - Regex, compiled once; named capture groups are
+//	                     exposed on the resulting PatternMatch.Captures
+//	expr               - Expr, an antonmedv/expr boolean expression over
+//	                     the event, using its Go names (Event.Severity,
+//	                     Event.Message, Event.Source, Event.EventID,
+//	                     Event.Field("key")); names are case-sensitive, so
+//	                     lower-case forms like event.severity do not compile
+//	sequence           - Sequence, an ordered list of keyword steps that
+//	                     must all occur within Window of the first step,
+//	                     touching at least MinSources distinct sources
 type PatternConfig struct {
 	Name        string   `yaml:"name"`
+	Type        string   `yaml:"type"`
 	Keywords    []string `yaml:"keywords"`
 	Severity    string   `yaml:"severity"`
 	Description string   `yaml:"description"`
 	Resolution  string   `yaml:"resolution"`
+
+	Regex string `yaml:"regex"`
+	Expr  string `yaml:"expr"`
+
+	Sequence   []string      `yaml:"sequence"`
+	Window     time.Duration `yaml:"window"`
+	MinSources int           `yaml:"min_sources"`
+
+	// Regexes, When, Cooldown, and MinOccurrences refine whatever Type
+	// already matched rather than selecting a matcher themselves: compiled
+	// into an analyzer.Rule (see analyzer.NewRuleSet), they narrow a
+	// pattern's matched events down to ones also satisfying these, then
+	// gate whether the narrowed result surfaces at all. A pattern that
+	// sets none of them skips rule evaluation entirely.
+	//
+	// Regexes are OR'd together and matched against each already-matched
+	// event's message. When is a CEL expression evaluated against the
+	// event (source, severity, timestamp, fields - fields is
+	// map[string]string, so compare a numeric field with e.g.
+	// `double(fields["http.status"]) >= 500.0`). Cooldown suppresses a
+	// repeat surfacing of this pattern within that long of its last one.
+	// MinOccurrences requires at least that many matched events before
+	// the pattern surfaces at all, to demote one-off noise.
+	Regexes        []string      `yaml:"regexes"`
+	When           string        `yaml:"when"`
+	Cooldown       time.Duration `yaml:"cooldown"`
+	MinOccurrences int           `yaml:"min_occurrences"`
 }
 
 type ThresholdConfig struct {
 	ErrorRateMultiplier float64 `yaml:"error_rate_multiplier"`
 	AnomalyStdDev       float64 `yaml:"anomaly_std_dev"`
 	MinConfidence       float64 `yaml:"min_confidence"`
+	EWMAAlpha           float64 `yaml:"ewma_alpha"`
 }
 
 type TimeRange struct {
@@ -65,6 +123,10 @@ func LoadConfig() *Config {
 
 func defaultConfig() *Config {
 	return &Config{
+		Storage: StorageConfig{
+			Driver: "sqlite",
+			DSN:    "incidents.db",
+		},
 		Collectors: map[string]CollectorConfig{
 			"windows_events": {
 				Enabled: true,
@@ -99,6 +161,7 @@ func defaultConfig() *Config {
 		Patterns: []PatternConfig{
 			{
 				Name:        "Database Connection Pool Exhaustion",
+				Type:        "keyword",
 				Keywords:    []string{"connection pool", "too many connections", "pool exhausted"},
 				Severity:    "CRITICAL",
 				Description: "Database connection pool is full",
@@ -106,6 +169,7 @@ func defaultConfig() *Config {
 			},
 			{
 				Name:        "Out of Memory",
+				Type:        "keyword",
 				Keywords:    []string{"out of memory", "oom", "heap", "memory exhausted"},
 				Severity:    "CRITICAL",
 				Description: "Application or system running out of memory",
@@ -113,6 +177,7 @@ func defaultConfig() *Config {
 			},
 			{
 				Name:        "Disk Space Exhausted",
+				Type:        "keyword",
 				Keywords:    []string{"disk full", "no space left", "disk quota exceeded"},
 				Severity:    "CRITICAL",
 				Description: "Disk space has been exhausted",
@@ -120,6 +185,7 @@ func defaultConfig() *Config {
 			},
 			{
 				Name:        "Network Timeout",
+				Type:        "keyword",
 				Keywords:    []string{"timeout", "connection refused", "network unreachable"},
 				Severity:    "HIGH",
 				Description: "Network connectivity issues detected",
@@ -127,6 +193,7 @@ func defaultConfig() *Config {
 			},
 			{
 				Name:        "Authentication Failure",
+				Type:        "keyword",
 				Keywords:    []string{"authentication failed", "unauthorized", "access denied", "invalid credentials"},
 				Severity:    "HIGH",
 				Description: "Authentication or authorization failures",
@@ -134,6 +201,7 @@ func defaultConfig() *Config {
 			},
 			{
 				Name:        "Deadlock Detected",
+				Type:        "keyword",
 				Keywords:    []string{"deadlock", "lock timeout", "waiting for lock"},
 				Severity:    "HIGH",
 				Description: "Database deadlock condition",
@@ -144,6 +212,11 @@ func defaultConfig() *Config {
 			ErrorRateMultiplier: 3.0,
 			AnomalyStdDev:       3.0,
 			MinConfidence:       0.7,
+			EWMAAlpha:           0.3,
+		},
+		Dependencies: map[string][]string{
+			"application": {"sql_server"},
+			"iis":         {"application", "sql_server"},
 		},
 	}
-}
\ No newline at end of file
+}
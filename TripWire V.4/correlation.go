@@ -0,0 +1,205 @@
+// internal/analyzer/correlation.go
+package analyzer
+
+import (
+	"sort"
+	"time"
+
+	"incident-analyzer/internal/storage"
+)
+
+const (
+	// correlationWindow bounds how far apart two nodes can occur and
+	// still be considered causally related; wider than detectAnomalies'
+	// 1-minute bins since a downstream service may take a while to start
+	// erroring after its dependency does.
+	correlationWindow = 2 * time.Minute
+
+	// dependencyBonus is added to an edge's temporal-proximity weight
+	// when config.Dependencies says the later node's source depends on
+	// the earlier one's, so a declared dependency always outweighs
+	// coincidental timing alone.
+	dependencyBonus = 1.0
+
+	pageRankDamping    = 0.85
+	pageRankIterations = 20
+)
+
+// correlationNode is one (source, pattern) pair observed in the analysis
+// window. A pattern match spanning several sources becomes several
+// nodes, since each source is a distinct candidate root cause even when
+// they all tripped the same pattern.
+type correlationNode struct {
+	source    string
+	pattern   string
+	events    []*storage.Event
+	firstSeen time.Time
+}
+
+// correlationGraph is a directed, weighted graph over correlationNodes.
+// An edge i->j means node i plausibly caused node j: i happened first,
+// within correlationWindow, optionally boosted by a declared service
+// dependency. Nodes are kept sorted by firstSeen so index order doubles
+// as chronological order.
+type correlationGraph struct {
+	nodes []*correlationNode
+	edges [][]float64 // edges[i][j] is the weight of i->j, 0 if none
+}
+
+// buildCorrelationGraph splits every pattern match into one node per
+// source it touched, then draws an edge from each node to every later
+// node within correlationWindow, weighted by how close together they
+// occurred and boosted by dependencies.
+func buildCorrelationGraph(patterns []PatternMatch, dependencies map[string][]string) *correlationGraph {
+	var nodes []*correlationNode
+	for _, pattern := range patterns {
+		bySource := make(map[string]*correlationNode)
+		var order []string
+		for _, event := range pattern.Events {
+			node, ok := bySource[event.Source]
+			if !ok {
+				node = &correlationNode{source: event.Source, pattern: pattern.Name, firstSeen: event.Timestamp}
+				bySource[event.Source] = node
+				order = append(order, event.Source)
+			}
+			node.events = append(node.events, event)
+			if event.Timestamp.Before(node.firstSeen) {
+				node.firstSeen = event.Timestamp
+			}
+		}
+		for _, source := range order {
+			nodes = append(nodes, bySource[source])
+		}
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].firstSeen.Before(nodes[j].firstSeen) })
+
+	edges := make([][]float64, len(nodes))
+	for i := range edges {
+		edges[i] = make([]float64, len(nodes))
+	}
+
+	dependsOn := func(downstream, upstream string) bool {
+		for _, dep := range dependencies[downstream] {
+			if dep == upstream {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i, from := range nodes {
+		for j, to := range nodes {
+			if i == j {
+				continue
+			}
+			delta := to.firstSeen.Sub(from.firstSeen)
+			if delta < 0 || delta > correlationWindow {
+				continue
+			}
+			weight := 1 - float64(delta)/float64(correlationWindow)
+			if dependsOn(to.source, from.source) {
+				weight += dependencyBonus
+			}
+			edges[i][j] = weight
+		}
+	}
+
+	return &correlationGraph{nodes: nodes, edges: edges}
+}
+
+// centrality runs weighted PageRank over g: a node fed by many
+// well-correlated upstream nodes scores higher, the same "good backlinks"
+// idea PageRank applies to hyperlinks, applied here to causal edges.
+func (g *correlationGraph) centrality() []float64 {
+	n := len(g.nodes)
+	if n == 0 {
+		return nil
+	}
+
+	outWeight := make([]float64, n)
+	for i := range g.edges {
+		for _, w := range g.edges[i] {
+			outWeight[i] += w
+		}
+	}
+
+	scores := make([]float64, n)
+	for i := range scores {
+		scores[i] = 1.0 / float64(n)
+	}
+
+	for iter := 0; iter < pageRankIterations; iter++ {
+		next := make([]float64, n)
+		for i := range next {
+			next[i] = (1 - pageRankDamping) / float64(n)
+		}
+		for i := 0; i < n; i++ {
+			if outWeight[i] == 0 {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				if g.edges[i][j] == 0 {
+					continue
+				}
+				next[j] += pageRankDamping * scores[i] * (g.edges[i][j] / outWeight[i])
+			}
+		}
+		scores = next
+	}
+
+	return scores
+}
+
+// descendants returns the indices of every node reachable by following
+// outgoing edges forward from i.
+func (g *correlationGraph) descendants(i int) map[int]bool {
+	visited := make(map[int]bool)
+	var walk func(int)
+	walk = func(cur int) {
+		for j, w := range g.edges[cur] {
+			if w == 0 || visited[j] {
+				continue
+			}
+			visited[j] = true
+			walk(j)
+		}
+	}
+	walk(i)
+	return visited
+}
+
+// chain greedily follows the strongest outgoing edge from node i,
+// building the causal chain reported as RootCause.Evidence. tightness is
+// the mean edge weight along the chain, normalized to roughly [0, 1].
+func (g *correlationGraph) chain(i int) (nodes []*correlationNode, tightness float64) {
+	nodes = []*correlationNode{g.nodes[i]}
+	visited := map[int]bool{i: true}
+	cur := i
+
+	var weightSum float64
+	var steps int
+	for {
+		best, bestWeight := -1, 0.0
+		for j, w := range g.edges[cur] {
+			if w > bestWeight && !visited[j] {
+				best, bestWeight = j, w
+			}
+		}
+		if best == -1 {
+			break
+		}
+		nodes = append(nodes, g.nodes[best])
+		weightSum += bestWeight
+		steps++
+		visited[best] = true
+		cur = best
+	}
+
+	if steps == 0 {
+		// No downstream node followed from i - it's its own whole chain,
+		// which is as temporally tight as a chain can get.
+		return nodes, 1
+	}
+	return nodes, (weightSum / float64(steps)) / (1 + dependencyBonus)
+}
@@ -5,7 +5,6 @@ import (
 	"context"
 	"fmt"
 	"math"
-	"strings"
 	"time"
 
 	"incident-analyzer/internal/config"
@@ -13,27 +12,44 @@ import (
 )
 
 type Engine struct {
-	store  *storage.SQLiteStore
-	config *config.Config
+	store    storage.Store
+	config   *config.Config
+	matchers []PatternMatcher
+	rules    *RuleSet
 }
 
 type AnalysisResult struct {
-	Summary       Summary
-	Timeline      []TimelineEvent
-	Patterns      []PatternMatch
-	Anomalies     []Anomaly
-	RootCause     *RootCause
+	Summary   Summary
+	Timeline  []TimelineEvent
+	Patterns  []PatternMatch
+	Anomalies []Anomaly
+	RootCause *RootCause
+	// CascadeDetected is true when RootCause's causal chain reaches
+	// across at least three distinct sources, set by determineRootCause
+	// from its correlation graph rather than a flat AffectedSources count.
+	CascadeDetected bool
 	Recommendations []string
-	Statistics    Statistics
+	Statistics      Statistics
+
+	// AnalysisID is this run's row ID once Analyze has persisted it via
+	// SaveResult; zero if persistence failed.
+	AnalysisID int64
+	// OccurrenceCount is how many times (including this run) an incident
+	// with the same Fingerprint has occurred in the last 24h.
+	// PreviousAnalysisID is the most recent prior run sharing that
+	// fingerprint, or zero if this is the first. Both are populated by
+	// loadOccurrenceHistory before generateRecommendations runs.
+	OccurrenceCount    int
+	PreviousAnalysisID int64
 }
 
 type Summary struct {
-	TotalEvents   int64
-	ErrorCount    int64
-	WarningCount  int64
-	ErrorRate     float64
-	TimeRange     config.TimeRange
-	Duration      time.Duration
+	TotalEvents     int64
+	ErrorCount      int64
+	WarningCount    int64
+	ErrorRate       float64
+	TimeRange       config.TimeRange
+	Duration        time.Duration
 	AffectedSources []string
 }
 
@@ -54,6 +70,9 @@ type PatternMatch struct {
 	FirstSeen   time.Time
 	LastSeen    time.Time
 	Events      []*storage.Event
+	// Captures holds named regex capture groups, one map per matched event,
+	// for patterns of Type "regex"; nil for every other matcher type.
+	Captures []map[string]string
 }
 
 type Anomaly struct {
@@ -88,18 +107,44 @@ type ErrorSummary struct {
 	Sources []string
 }
 
+// Baseline holds the statistics detectAnomalies compares a live analysis
+// window against. Event statistics are per-minute event counts so they
+// line up with how the window itself gets bucketed; AvgErrorRate and
+// ErrorRateStdDev are error percentages. BySource lets a single noisy
+// source trip a comparison against its own history even when the global
+// rate looks unremarkable; BySeverity is computed and stored alongside it,
+// but detectAnomalies does not compare against it. Seasonal holds a
+// baseline per hour-of-day/day-of-week bucket so a normally busy 9am
+// doesn't read as an anomaly against the flat overall average.
 type Baseline struct {
 	AvgEventsPerMinute float64
-	AvgErrorRate       float64
 	StdDev             float64
+	AvgErrorRate       float64
+	ErrorRateStdDev    float64
 	CommonPatterns     map[string]int
+	BySource           map[string]SeriesStats
+	BySeverity         map[string]SeriesStats
+	Seasonal           map[SeasonalKey]SeriesStats
 }
 
-func NewEngine(store *storage.SQLiteStore, cfg *config.Config) *Engine {
+func NewEngine(store storage.Store, cfg *config.Config) *Engine {
 	return &Engine{
-		store:  store,
-		config: cfg,
+		store:    store,
+		config:   cfg,
+		matchers: newPatternMatchers(cfg.Patterns),
+		rules:    NewRuleSet(cfg.Patterns),
+	}
+}
+
+// PatternMetrics reports per-pattern evaluation counts and timing, keyed
+// by pattern name, so an operator can see which rules are expensive or
+// rarely fire.
+func (e *Engine) PatternMetrics() map[string]MatcherMetrics {
+	metrics := make(map[string]MatcherMetrics, len(e.matchers))
+	for _, m := range e.matchers {
+		metrics[m.Config().Name] = m.Metrics()
 	}
+	return metrics
 }
 
 func (e *Engine) Analyze(ctx context.Context, timeRange config.TimeRange, baseline *Baseline) (*AnalysisResult, error) {
@@ -147,10 +192,7 @@ func (e *Engine) Analyze(ctx context.Context, timeRange config.TimeRange, baseli
 	result.Timeline = e.buildTimeline(events, result.Patterns, result.Anomalies)
 
 	// Determine root cause
-	result.RootCause = e.determineRootCause(result.Patterns, result.Anomalies, events)
-
-	// Generate recommendations
-	result.Recommendations = e.generateRecommendations(result)
+	result.RootCause, result.CascadeDetected = e.determineRootCause(result.Patterns, result.Anomalies, events)
 
 	// Get affected sources
 	sources := make(map[string]bool)
@@ -163,6 +205,18 @@ func (e *Engine) Analyze(ctx context.Context, timeRange config.TimeRange, baseli
 		result.Summary.AffectedSources = append(result.Summary.AffectedSources, source)
 	}
 
+	// Recognize recurring incidents so generateRecommendations can dedup
+	// against prior guidance instead of repeating it every run.
+	e.loadOccurrenceHistory(result, timeRange.End)
+
+	// Generate recommendations
+	result.Recommendations = e.generateRecommendations(result)
+
+	// Persist this run so future runs can recognize it recurring.
+	if id, err := e.SaveResult(result, timeRange.End); err == nil {
+		result.AnalysisID = id
+	}
+
 	return result, nil
 }
 
@@ -223,92 +277,184 @@ func (e *Engine) buildStatistics(events []*storage.Event, timeRange config.TimeR
 	return stats
 }
 
+// detectPatterns delegates to e.matchers, one PatternMatcher per
+// configured pattern (see newPatternMatchers), so keyword, regex, expr,
+// and sequence rules all evaluate through the same interface. Most
+// matchers produce at most one PatternMatch per Evaluate call, but
+// sequenceMatcher appends one per independent completion found in the
+// window, so the result is merged by name before returning - callers
+// (reporters/sarif.go in particular) assume one PatternMatch per pattern
+// name.
 func (e *Engine) detectPatterns(events []*storage.Event) []PatternMatch {
 	var matches []PatternMatch
-
-	for _, patternConfig := range e.config.Patterns {
-		var patternEvents []*storage.Event
-		var firstSeen, lastSeen time.Time
-
-		for _, event := range events {
-			message := strings.ToLower(event.Message)
-			matched := false
-
-			for _, keyword := range patternConfig.Keywords {
-				if strings.Contains(message, strings.ToLower(keyword)) {
-					matched = true
-					break
-				}
+	for _, matcher := range e.matchers {
+		for _, match := range matcher.Evaluate(events) {
+			if refined, ok := e.rules.apply(match); ok {
+				matches = append(matches, refined)
 			}
+		}
+	}
+	return mergePatternMatchesByName(matches)
+}
 
-			if matched {
-				patternEvents = append(patternEvents, event)
-				if firstSeen.IsZero() || event.Timestamp.Before(firstSeen) {
-					firstSeen = event.Timestamp
-				}
-				if lastSeen.IsZero() || event.Timestamp.After(lastSeen) {
-					lastSeen = event.Timestamp
-				}
-			}
+// mergePatternMatchesByName combines same-name PatternMatches (e.g.
+// multiple sequenceMatcher completions in one window) into one, summing
+// Occurrences and concatenating Events/Captures in order while keeping
+// the earliest FirstSeen and latest LastSeen. Severity/Description/
+// Resolution are shared by construction (they come from the same
+// PatternConfig), so the first match's are kept as-is. Order of first
+// appearance is preserved.
+func mergePatternMatchesByName(matches []PatternMatch) []PatternMatch {
+	merged := make([]PatternMatch, 0, len(matches))
+	index := make(map[string]int, len(matches))
+
+	for _, match := range matches {
+		i, ok := index[match.Name]
+		if !ok {
+			index[match.Name] = len(merged)
+			merged = append(merged, match)
+			continue
 		}
 
-		if len(patternEvents) > 0 {
-			matches = append(matches, PatternMatch{
-				Name:        patternConfig.Name,
-				Severity:    patternConfig.Severity,
-				Description: patternConfig.Description,
-				Resolution:  patternConfig.Resolution,
-				Occurrences: len(patternEvents),
-				FirstSeen:   firstSeen,
-				LastSeen:    lastSeen,
-				Events:      patternEvents,
-			})
+		existing := &merged[i]
+		existing.Occurrences += match.Occurrences
+		existing.Events = append(existing.Events, match.Events...)
+		existing.Captures = append(existing.Captures, match.Captures...)
+		if match.FirstSeen.Before(existing.FirstSeen) {
+			existing.FirstSeen = match.FirstSeen
+		}
+		if match.LastSeen.After(existing.LastSeen) {
+			existing.LastSeen = match.LastSeen
 		}
 	}
 
-	return matches
+	return merged
 }
 
+// detectAnomalies runs three detectors over the analysis window, each
+// bucketed into 1-minute bins so every Anomaly carries the actual
+// timestamp of the offending bin rather than timeRange.Start:
+//
+//  1. a rolling z-score against the historical baseline, seasonally
+//     adjusted so a bin that's only anomalous against the flat overall
+//     average (e.g. a normally busy 9am) doesn't get flagged;
+//  2. an EWMA detector that catches a sustained creeping rate no single
+//     bin's z-score would trip;
+//  3. per-source z-scores against that source's own baseline, catching a
+//     single noisy source masked by overall volume.
+//
+// Error-rate spikes are still compared once over the whole window, same
+// as before - error rate isn't naturally a per-minute quantity once a
+// bin has only a handful of events.
 func (e *Engine) detectAnomalies(events []*storage.Event, baseline *Baseline, timeRange config.TimeRange) []Anomaly {
 	var anomalies []Anomaly
 
-	// Calculate current metrics
-	duration := timeRange.End.Sub(timeRange.Start).Minutes()
-	eventsPerMinute := float64(len(events)) / duration
-
-	// Check for event rate spike
-	if eventsPerMinute > baseline.AvgEventsPerMinute+(e.config.Thresholds.AnomalyStdDev*baseline.StdDev) {
-		deviation := (eventsPerMinute - baseline.AvgEventsPerMinute) / baseline.AvgEventsPerMinute * 100
-		anomalies = append(anomalies, Anomaly{
-			Type:        "Event Rate Spike",
-			Timestamp:   timeRange.Start,
-			Severity:    "HIGH",
-			Description: fmt.Sprintf("Event rate %.1f/min is %.1f%% above baseline (%.1f/min)", eventsPerMinute, deviation, baseline.AvgEventsPerMinute),
-			Value:       eventsPerMinute,
-			Baseline:    baseline.AvgEventsPerMinute,
-			Deviation:   deviation,
-		})
+	threshold := e.config.Thresholds.AnomalyStdDev
+	bins := bucketPerMinute(events, timeRange.Start, timeRange.End, nil)
+	series := counts(bins)
+
+	if baseline.StdDev > 0 {
+		for _, bin := range bins {
+			if bin.count == 0 {
+				continue
+			}
+
+			globalZ := (float64(bin.count) - baseline.AvgEventsPerMinute) / baseline.StdDev
+			if math.Abs(globalZ) <= threshold {
+				continue
+			}
+
+			if seasonal, ok := baseline.Seasonal[seasonalKey(bin.start)]; ok && seasonal.StdDev > 0 {
+				seasonalZ := (float64(bin.count) - seasonal.Mean) / seasonal.StdDev
+				if math.Abs(seasonalZ) <= threshold {
+					continue // expected for this hour/weekday, not an anomaly
+				}
+			}
+
+			deviation := (float64(bin.count) - baseline.AvgEventsPerMinute) / baseline.AvgEventsPerMinute * 100
+			anomalies = append(anomalies, Anomaly{
+				Type:        "Event Rate Spike",
+				Timestamp:   bin.start,
+				Severity:    "HIGH",
+				Description: fmt.Sprintf("%d events/min at %s is %.1f%% above baseline (%.1f/min, z=%.1f)", bin.count, bin.start.Format("15:04"), deviation, baseline.AvgEventsPerMinute, globalZ),
+				Value:       float64(bin.count),
+				Baseline:    baseline.AvgEventsPerMinute,
+				Deviation:   deviation,
+			})
+		}
 	}
 
-	// Check for error rate increase
-	errorCount := 0
-	for _, event := range events {
-		if event.Severity == "ERROR" || event.Severity == "FATAL" || event.Severity == "CRITICAL" {
-			errorCount++
+	alpha := e.config.Thresholds.EWMAAlpha
+	if alpha <= 0 {
+		alpha = 0.3
+	}
+	if smoothed := ewma(series, alpha); len(smoothed) > 0 {
+		residuals := make([]float64, len(smoothed))
+		for i, s := range smoothed {
+			residuals[i] = s - baseline.AvgEventsPerMinute
+		}
+		if residualStdDev := standardDeviation(residuals); residualStdDev > 0 {
+			for i, r := range residuals {
+				if math.Abs(r)/residualStdDev <= threshold {
+					continue
+				}
+				anomalies = append(anomalies, Anomaly{
+					Type:        "EWMA Drift",
+					Timestamp:   bins[i].start,
+					Severity:    "MEDIUM",
+					Description: fmt.Sprintf("Smoothed rate %.1f/min at %s has drifted %.1f residual-stddevs from baseline (%.1f/min)", smoothed[i], bins[i].start.Format("15:04"), r/residualStdDev, baseline.AvgEventsPerMinute),
+					Value:       smoothed[i],
+					Baseline:    baseline.AvgEventsPerMinute,
+					Deviation:   r,
+				})
+			}
 		}
 	}
-	currentErrorRate := float64(errorCount) / float64(len(events)) * 100
-
-	if currentErrorRate > baseline.AvgErrorRate*e.config.Thresholds.ErrorRateMultiplier {
-		anomalies = append(anomalies, Anomaly{
-			Type:        "Error Rate Spike",
-			Timestamp:   timeRange.Start,
-			Severity:    "CRITICAL",
-			Description: fmt.Sprintf("Error rate %.1f%% is %.1fx baseline (%.1f%%)", currentErrorRate, currentErrorRate/baseline.AvgErrorRate, baseline.AvgErrorRate),
-			Value:       currentErrorRate,
-			Baseline:    baseline.AvgErrorRate,
-			Deviation:   currentErrorRate - baseline.AvgErrorRate,
-		})
+
+	if total := len(events); total > 0 && baseline.AvgErrorRate > 0 {
+		errorCount := 0
+		for _, event := range events {
+			if isError(event) {
+				errorCount++
+			}
+		}
+		currentErrorRate := float64(errorCount) / float64(total) * 100
+
+		if currentErrorRate > baseline.AvgErrorRate*e.config.Thresholds.ErrorRateMultiplier {
+			anomalies = append(anomalies, Anomaly{
+				Type:        "Error Rate Spike",
+				Timestamp:   timeRange.Start,
+				Severity:    "CRITICAL",
+				Description: fmt.Sprintf("Error rate %.1f%% is %.1fx baseline (%.1f%%)", currentErrorRate, currentErrorRate/baseline.AvgErrorRate, baseline.AvgErrorRate),
+				Value:       currentErrorRate,
+				Baseline:    baseline.AvgErrorRate,
+				Deviation:   currentErrorRate - baseline.AvgErrorRate,
+			})
+		}
+	}
+
+	for source, sourceBaseline := range baseline.BySource {
+		if sourceBaseline.StdDev <= 0 {
+			continue
+		}
+		for _, bin := range bucketPerMinute(events, timeRange.Start, timeRange.End, func(ev *storage.Event) bool { return ev.Source == source }) {
+			if bin.count == 0 {
+				continue
+			}
+			z := (float64(bin.count) - sourceBaseline.Mean) / sourceBaseline.StdDev
+			if math.Abs(z) <= threshold {
+				continue
+			}
+			anomalies = append(anomalies, Anomaly{
+				Type:        "Source Rate Spike",
+				Timestamp:   bin.start,
+				Severity:    "HIGH",
+				Description: fmt.Sprintf("%s: %d events/min at %s is %.1f stddevs above its baseline (%.1f/min)", source, bin.count, bin.start.Format("15:04"), z, sourceBaseline.Mean),
+				Value:       float64(bin.count),
+				Baseline:    sourceBaseline.Mean,
+				Deviation:   z,
+			})
+		}
 	}
 
 	return anomalies
@@ -358,77 +504,110 @@ func (e *Engine) buildTimeline(events []*storage.Event, patterns []PatternMatch,
 	return timeline
 }
 
-func (e *Engine) determineRootCause(patterns []PatternMatch, anomalies []Anomaly, events []*storage.Event) *RootCause {
-	// Find the highest severity pattern with earliest occurrence
-	var bestPattern *PatternMatch
-	var earliestTime time.Time
-
-	for i, pattern := range patterns {
-		if pattern.Severity == "CRITICAL" {
-			if bestPattern == nil || pattern.FirstSeen.Before(earliestTime) {
-				bestPattern = &patterns[i]
-				earliestTime = pattern.FirstSeen
-			}
+// determineRootCause builds a correlation graph over every (source,
+// pattern) node from patterns, weighted by temporal proximity and
+// declared service dependencies (config.Dependencies), and picks the
+// earliest node whose descendants cover the largest fraction of the
+// graph as the root cause - rather than simply the earliest CRITICAL
+// pattern. Confidence is graph coverage times the causal chain's
+// temporal tightness, not a sum of ad-hoc bumps. CascadeDetected reports
+// whether that chain spans at least three distinct sources.
+func (e *Engine) determineRootCause(patterns []PatternMatch, anomalies []Anomaly, events []*storage.Event) (*RootCause, bool) {
+	if len(patterns) == 0 {
+		return nil, false
+	}
+
+	graph := buildCorrelationGraph(patterns, e.config.Dependencies)
+	n := len(graph.nodes)
+	if n == 0 {
+		return nil, false
+	}
+	centralityScores := graph.centrality()
+
+	bestIdx, maxCoverage := 0, 0.0
+	coverage := make([]float64, n)
+	for i := range graph.nodes {
+		coverage[i] = float64(len(graph.descendants(i))) / float64(n)
+		if coverage[i] > maxCoverage {
+			maxCoverage = coverage[i]
 		}
 	}
-
-	if bestPattern == nil {
-		return nil
+	// graph.nodes is sorted by firstSeen, so the first node matching
+	// maxCoverage is the earliest one achieving it.
+	for i := range graph.nodes {
+		if coverage[i] == maxCoverage {
+			bestIdx = i
+			break
+		}
 	}
 
-	// Calculate confidence based on evidence
-	confidence := 0.5
-	evidence := []string{
-		fmt.Sprintf("%d occurrences of pattern '%s'", bestPattern.Occurrences, bestPattern.Name),
+	chainNodes, tightness := graph.chain(bestIdx)
+
+	sourcesInChain := make(map[string]bool)
+	for _, node := range chainNodes {
+		sourcesInChain[node.source] = true
 	}
+	cascadeDetected := len(sourcesInChain) >= 3
 
-	// Increase confidence with more occurrences
-	if bestPattern.Occurrences > 10 {
-		confidence += 0.2
+	confidence := maxCoverage * tightness
+	if confidence < 0.05 {
+		confidence = 0.05
 	}
-	if bestPattern.Occurrences > 50 {
-		confidence += 0.1
+	if confidence > 0.95 {
+		confidence = 0.95
 	}
 
-	// Increase confidence if anomalies detected
-	if len(anomalies) > 0 {
-		confidence += 0.1
-		evidence = append(evidence, fmt.Sprintf("%d anomalies detected", len(anomalies)))
-	}
+	root := chainNodes[0]
+	description, resolution := patternDetails(patterns, root.pattern)
 
-	// Increase confidence if multiple sources affected
-	sources := make(map[string]bool)
-	for _, event := range bestPattern.Events {
-		sources[event.Source] = true
+	evidence := []string{
+		fmt.Sprintf("%d of %d correlated nodes reachable from this cause (%.0f%% coverage, centrality %.2f)", len(graph.descendants(bestIdx)), n, maxCoverage*100, centralityScores[bestIdx]),
 	}
-	if len(sources) > 2 {
-		confidence += 0.1
-		evidence = append(evidence, fmt.Sprintf("%d sources affected", len(sources)))
+	for _, node := range chainNodes {
+		evidence = append(evidence, fmt.Sprintf("%s: %s (%d events, first at %s)", node.source, node.pattern, len(node.events), node.firstSeen.Format("15:04:05")))
+	}
+	if len(anomalies) > 0 {
+		evidence = append(evidence, fmt.Sprintf("%d anomalies detected in the same window", len(anomalies)))
 	}
-
-	confidence = math.Min(confidence, 0.95)
 
 	return &RootCause{
-		Pattern:     bestPattern.Name,
+		Pattern:     root.pattern,
 		Confidence:  confidence,
-		Timestamp:   bestPattern.FirstSeen,
-		Description: bestPattern.Description,
+		Timestamp:   root.firstSeen,
+		Description: description,
 		Evidence:    evidence,
-		Resolution:  bestPattern.Resolution,
+		Resolution:  resolution,
+	}, cascadeDetected
+}
+
+// patternDetails looks up the Description and Resolution configured for
+// the first PatternMatch named name, since correlationNode only carries
+// the pattern name forward.
+func patternDetails(patterns []PatternMatch, name string) (description, resolution string) {
+	for _, p := range patterns {
+		if p.Name == name {
+			return p.Description, p.Resolution
+		}
 	}
+	return "", ""
 }
 
 func (e *Engine) generateRecommendations(result *AnalysisResult) []string {
 	var recommendations []string
 
-	// Based on root cause
-	if result.RootCause != nil {
+	// A recurring incident already got this guidance on a prior run -
+	// point at it instead of repeating it verbatim every time it recurs.
+	if result.OccurrenceCount > 1 {
+		recommendations = append(recommendations, fmt.Sprintf(
+			"This is the %s occurrence of this incident in the last 24h - see run #%d for the original guidance.",
+			ordinal(result.OccurrenceCount), result.PreviousAnalysisID))
+	} else if result.RootCause != nil {
 		recommendations = append(recommendations, "IMMEDIATE:")
 		recommendations = append(recommendations, result.RootCause.Resolution)
 	}
 
-	// Based on affected sources
-	if len(result.Summary.AffectedSources) > 3 {
+	// Based on the root cause's causal chain
+	if result.CascadeDetected {
 		recommendations = append(recommendations, "")
 		recommendations = append(recommendations, "CASCADING FAILURE DETECTED:")
 		recommendations = append(recommendations, "  1. Focus on earliest failure point")
@@ -453,8 +632,13 @@ func (e *Engine) generateRecommendations(result *AnalysisResult) []string {
 	return recommendations
 }
 
-func LoadBaseline(path string) (*Baseline, error) {
-	store, err := storage.NewSQLiteStore(path)
+// LoadBaseline opens the store selected by cfg.Storage (driver + DSN, the
+// same as every other entry point - see storage.Open) and computes a
+// Baseline from every event it holds. Nothing is excluded: when the live
+// analysis window is read from the same store, its events are part of the
+// baseline too.
+func LoadBaseline(cfg *config.Config) (*Baseline, error) {
+	store, err := storage.Open(cfg.Storage.Driver, cfg.Storage.DSN)
 	if err != nil {
 		return nil, err
 	}
@@ -465,17 +649,17 @@ func LoadBaseline(path string) (*Baseline, error) {
 		return nil, err
 	}
 
-	duration := stats.EndTime.Sub(stats.StartTime).Minutes()
-	eventsPerMinute := float64(stats.TotalEvents) / duration
+	events, err := store.GetEvents(stats.StartTime, stats.EndTime, "all")
+	if err != nil {
+		return nil, err
+	}
 
-	errorRate, _ := store.GetErrorRate(stats.StartTime, stats.EndTime)
+	errorRate, err := store.GetErrorRate(stats.StartTime, stats.EndTime)
+	if err != nil {
+		return nil, err
+	}
 
-	return &Baseline{
-		AvgEventsPerMinute: eventsPerMinute,
-		AvgErrorRate:       errorRate,
-		StdDev:             eventsPerMinute * 0.2, // Simplified - calculate actual stddev
-		CommonPatterns:     make(map[string]int),
-	}, nil
+	return buildBaseline(events, stats.StartTime, stats.EndTime, errorRate), nil
 }
 
 func contains(slice []string, item string) bool {
@@ -485,4 +669,4 @@ func contains(slice []string, item string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
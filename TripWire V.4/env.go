@@ -0,0 +1,76 @@
+// internal/config/env.go
+package config
+
+import (
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envPrefix is prepended to every override variable's name, e.g.
+// INCIDENT_ANALYZER_THRESHOLDS_MIN_CONFIDENCE overrides
+// Config.Thresholds.MinConfidence.
+const envPrefix = "INCIDENT_ANALYZER_"
+
+// applyEnvOverrides walks cfg's fields, recursing into nested structs,
+// and for each scalar field whose yaml-tag path (joined with "_" and
+// upper-cased) has a matching INCIDENT_ANALYZER_ environment variable
+// set, overwrites the field with the parsed value. Maps and slices
+// (Collectors, Patterns, Dependencies) don't have a single env-var
+// representation and are left untouched - overriding one of those belongs
+// in a layered config file via Config.Merge instead.
+func applyEnvOverrides(cfg *Config) {
+	applyEnvOverridesTo(reflect.ValueOf(cfg).Elem(), envPrefix)
+}
+
+func applyEnvOverridesTo(v reflect.Value, prefix string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("yaml")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := prefix + strings.ToUpper(strings.SplitN(tag, ",", 2)[0])
+		fv := v.Field(i)
+
+		if fv.Kind() == reflect.Struct {
+			applyEnvOverridesTo(fv, name+"_")
+			continue
+		}
+
+		if raw, ok := os.LookupEnv(name); ok {
+			setScalar(fv, raw)
+		}
+	}
+}
+
+// setScalar parses raw into fv's concrete type, leaving fv unchanged if
+// raw doesn't parse. Unrecognized types (maps, slices, ...) are no-ops.
+func setScalar(fv reflect.Value, raw string) {
+	switch ptr := fv.Addr().Interface().(type) {
+	case *string:
+		*ptr = raw
+	case *bool:
+		if b, err := strconv.ParseBool(raw); err == nil {
+			*ptr = b
+		}
+	case *int:
+		if n, err := strconv.Atoi(raw); err == nil {
+			*ptr = n
+		}
+	case *int64:
+		if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			*ptr = n
+		}
+	case *float64:
+		if f, err := strconv.ParseFloat(raw, 64); err == nil {
+			*ptr = f
+		}
+	case *time.Duration:
+		if d, err := time.ParseDuration(raw); err == nil {
+			*ptr = d
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// internal/storage/fts.go
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// initializeFTS creates the events_fts virtual table and its maintenance
+// triggers when the linked sqlite3 build supports FTS5. mattn/go-sqlite3
+// only bundles FTS5 when built with the "sqlite_fts5" build tag (or
+// CGO_ENABLED with a recent enough system sqlite3), so this is detected at
+// runtime rather than assumed - stores built without it transparently fall
+// back to the LIKE-based search in searchMessagesLike.
+func (s *SQLiteStore) initializeFTS() error {
+	s.ftsAvailable = s.detectFTS5()
+	if !s.ftsAvailable {
+		return nil
+	}
+
+	schema := `
+	CREATE VIRTUAL TABLE IF NOT EXISTS events_fts USING fts5(
+		message, content='events', content_rowid='id'
+	);
+
+	CREATE TRIGGER IF NOT EXISTS events_fts_ai AFTER INSERT ON events BEGIN
+		INSERT INTO events_fts(rowid, message) VALUES (new.id, new.message);
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS events_fts_ad AFTER DELETE ON events BEGIN
+		INSERT INTO events_fts(events_fts, rowid, message) VALUES ('delete', old.id, old.message);
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS events_fts_au AFTER UPDATE ON events BEGIN
+		INSERT INTO events_fts(events_fts, rowid, message) VALUES ('delete', old.id, old.message);
+		INSERT INTO events_fts(rowid, message) VALUES (new.id, new.message);
+	END;
+	`
+
+	_, err := s.db.Exec(schema)
+	return err
+}
+
+func (s *SQLiteStore) detectFTS5() bool {
+	rows, err := s.db.Query("PRAGMA compile_options")
+	if err != nil {
+		return false
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var option string
+		if err := rows.Scan(&option); err != nil {
+			return false
+		}
+		if strings.Contains(option, "ENABLE_FTS5") {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchMessages searches event messages. When the build has FTS5, query
+// is an FTS5 MATCH expression (supports AND/OR/NEAR, phrase queries in
+// "quotes", and column filters) and results are ranked by BM25. Otherwise
+// it falls back to a plain substring LIKE match with no ranking.
+func (s *SQLiteStore) SearchMessages(query string, start, end time.Time, limit int) ([]SearchResult, error) {
+	if s.ftsAvailable {
+		return s.searchMessagesFTS(query, start, end, limit)
+	}
+	return s.searchMessagesLike(query, start, end, limit)
+}
+
+// searchMessagesFTS joins events_fts back to events on rowid so the result
+// carries the full Event, not just the indexed message column. bm25() is
+// more negative for better matches, so rank ascending is most-relevant-first.
+func (s *SQLiteStore) searchMessagesFTS(matchExpr string, start, end time.Time, limit int) ([]SearchResult, error) {
+	rows, err := s.readDB.Query(`
+		SELECT e.id, e.timestamp, e.source, e.severity, e.message, e.event_id, e.raw_data,
+		       bm25(events_fts) as rank
+		FROM events_fts
+		JOIN events e ON e.id = events_fts.rowid
+		WHERE events_fts MATCH ?
+		AND e.timestamp BETWEEN ? AND ?
+		ORDER BY rank
+		LIMIT ?
+	`, matchExpr, start, end, limit)
+	if err != nil {
+		return nil, fmt.Errorf("fts query: %w", err)
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		event := &Event{}
+		var rawData sql.NullString
+		var rank float64
+		if err := rows.Scan(&event.ID, &event.Timestamp, &event.Source, &event.Severity, &event.Message, &event.EventID, &rawData, &rank); err != nil {
+			return nil, err
+		}
+		event.Metadata = decodeMetadata(rawData)
+		results = append(results, SearchResult{Event: event, Rank: rank})
+	}
+
+	return results, rows.Err()
+}
+
+func (s *SQLiteStore) searchMessagesLike(pattern string, start, end time.Time, limit int) ([]SearchResult, error) {
+	rows, err := s.readDB.Query(`
+		SELECT id, timestamp, source, severity, message, event_id, raw_data
+		FROM events
+		WHERE timestamp BETWEEN ? AND ?
+		AND message LIKE ?
+		ORDER BY timestamp DESC
+		LIMIT ?
+	`, start, end, "%"+pattern+"%", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, SearchResult{Event: event, Rank: 0})
+	}
+
+	return results, rows.Err()
+}
+
+// RebuildSearchIndex repopulates events_fts from scratch, e.g. after
+// restoring a database dump taken without FTS5 or after a mass rewrite of
+// message content. It is a no-op (returning an error) when FTS5 isn't
+// available.
+func (s *SQLiteStore) RebuildSearchIndex() error {
+	if !s.ftsAvailable {
+		return fmt.Errorf("storage: FTS5 not available in this sqlite3 build")
+	}
+	_, err := s.db.Exec(`INSERT INTO events_fts(events_fts) VALUES ('rebuild')`)
+	return err
+}
@@ -0,0 +1,151 @@
+// internal/analyzer/history.go
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// recurrenceWindow is how far back loadOccurrenceHistory looks for prior
+// runs sharing the current one's fingerprint.
+const recurrenceWindow = 24 * time.Hour
+
+// Fingerprint derives a stable identity for an AnalysisResult from its
+// pattern names, affected sources, and root-cause pattern - modeled on
+// Bosun's AlertKey, so the same underlying incident recurring across runs
+// hashes to the same value even though event counts and timestamps
+// differ each time.
+func Fingerprint(result *AnalysisResult) string {
+	patternNames := make([]string, 0, len(result.Patterns))
+	for _, p := range result.Patterns {
+		patternNames = append(patternNames, p.Name)
+	}
+	sort.Strings(patternNames)
+
+	sources := append([]string(nil), result.Summary.AffectedSources...)
+	sort.Strings(sources)
+
+	rootCause := ""
+	if result.RootCause != nil {
+		rootCause = result.RootCause.Pattern
+	}
+
+	key := strings.Join(patternNames, ",") + "|" + strings.Join(sources, ",") + "|" + rootCause
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// SaveResult persists result to the store keyed by its Fingerprint, so a
+// later run can recognize the same incident recurring via
+// GetAnalysisByFingerprint.
+func (e *Engine) SaveResult(result *AnalysisResult, timestamp time.Time) (int64, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode analysis result: %w", err)
+	}
+	return e.store.SaveAnalysis(Fingerprint(result), timestamp, string(data))
+}
+
+// loadOccurrenceHistory looks up every prior run sharing result's
+// fingerprint within recurrenceWindow of asOf, and sets
+// result.OccurrenceCount/PreviousAnalysisID accordingly. A lookup failure
+// is treated as "no history" rather than propagated - occurrence
+// tracking is a convenience, not something that should fail an analysis.
+func (e *Engine) loadOccurrenceHistory(result *AnalysisResult, asOf time.Time) {
+	result.OccurrenceCount = 1
+
+	prior, err := e.store.GetAnalysisByFingerprint(Fingerprint(result), asOf.Add(-recurrenceWindow))
+	if err != nil || len(prior) == 0 {
+		return
+	}
+	result.OccurrenceCount = len(prior) + 1
+	result.PreviousAnalysisID = prior[0].ID
+}
+
+// ordinal renders n as "1st", "2nd", "3rd", "4th", etc.
+func ordinal(n int) string {
+	if n%100 >= 11 && n%100 <= 13 {
+		return fmt.Sprintf("%dth", n)
+	}
+	switch n % 10 {
+	case 1:
+		return fmt.Sprintf("%dst", n)
+	case 2:
+		return fmt.Sprintf("%dnd", n)
+	case 3:
+		return fmt.Sprintf("%drd", n)
+	default:
+		return fmt.Sprintf("%dth", n)
+	}
+}
+
+// AnalysisDiff is the difference between two AnalysisResults for the same
+// (or a recurring) incident: what's new, what's resolved, and how the
+// headline numbers moved.
+type AnalysisDiff struct {
+	NewPatterns             []string
+	ResolvedPatterns        []string
+	ErrorRateChange         float64
+	NewlyAffectedSources    []string
+	NoLongerAffectedSources []string
+}
+
+// Diff compares curr against prev - typically the prior run reported via
+// curr.PreviousAnalysisID - and reports what changed between them.
+func (e *Engine) Diff(prev, curr *AnalysisResult) *AnalysisDiff {
+	diff := &AnalysisDiff{}
+	if prev == nil || curr == nil {
+		return diff
+	}
+
+	diff.ErrorRateChange = curr.Summary.ErrorRate - prev.Summary.ErrorRate
+
+	prevPatterns := make(map[string]bool, len(prev.Patterns))
+	for _, p := range prev.Patterns {
+		prevPatterns[p.Name] = true
+	}
+	currPatterns := make(map[string]bool, len(curr.Patterns))
+	for _, p := range curr.Patterns {
+		currPatterns[p.Name] = true
+	}
+	for name := range currPatterns {
+		if !prevPatterns[name] {
+			diff.NewPatterns = append(diff.NewPatterns, name)
+		}
+	}
+	for name := range prevPatterns {
+		if !currPatterns[name] {
+			diff.ResolvedPatterns = append(diff.ResolvedPatterns, name)
+		}
+	}
+	sort.Strings(diff.NewPatterns)
+	sort.Strings(diff.ResolvedPatterns)
+
+	prevSources := make(map[string]bool, len(prev.Summary.AffectedSources))
+	for _, s := range prev.Summary.AffectedSources {
+		prevSources[s] = true
+	}
+	currSources := make(map[string]bool, len(curr.Summary.AffectedSources))
+	for _, s := range curr.Summary.AffectedSources {
+		currSources[s] = true
+	}
+	for s := range currSources {
+		if !prevSources[s] {
+			diff.NewlyAffectedSources = append(diff.NewlyAffectedSources, s)
+		}
+	}
+	for s := range prevSources {
+		if !currSources[s] {
+			diff.NoLongerAffectedSources = append(diff.NoLongerAffectedSources, s)
+		}
+	}
+	sort.Strings(diff.NewlyAffectedSources)
+	sort.Strings(diff.NoLongerAffectedSources)
+
+	return diff
+}
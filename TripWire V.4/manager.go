@@ -5,8 +5,10 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"incident-analyzer/internal/config"
+	"incident-analyzer/internal/metrics"
 	"incident-analyzer/internal/storage"
 )
 
@@ -17,11 +19,12 @@ type Collector interface {
 
 type Manager struct {
 	config     *config.Config
-	store      *storage.SQLiteStore
+	store      storage.Store
 	collectors []Collector
+	metrics    *metrics.Registry
 }
 
-func NewManager(cfg *config.Config, store *storage.SQLiteStore) *Manager {
+func NewManager(cfg *config.Config, store storage.Store) *Manager {
 	m := &Manager{
 		config: cfg,
 		store:  store,
@@ -41,6 +44,15 @@ func NewManager(cfg *config.Config, store *storage.SQLiteStore) *Manager {
 	return m
 }
 
+// SetRegistry points m at registry so CollectAll/Run feed it their
+// events-collected/error/write-latency counters for /metrics to scrape.
+// A Manager with no registry set (the zero value, nil) just skips
+// observing - this is an optional collaborator, not a constructor arg,
+// since most callers (tests, one-off CLI runs) have no need for it.
+func (m *Manager) SetRegistry(registry *metrics.Registry) {
+	m.metrics = registry
+}
+
 func (m *Manager) CollectAll(ctx context.Context, timeRange config.TimeRange, sources []string) error {
 	var wg sync.WaitGroup
 	errChan := make(chan error, len(m.collectors))
@@ -61,17 +73,38 @@ func (m *Manager) CollectAll(ctx context.Context, timeRange config.TimeRange, so
 			events, err := c.Collect(ctx, timeRange)
 			if err != nil {
 				fmt.Printf("❌ Failed: %v\n", err)
+				if m.metrics != nil {
+					m.metrics.ObserveCollectorError(c.Name())
+				}
 				errChan <- fmt.Errorf("%s: %w", c.Name(), err)
 				return
 			}
 
 			// Store events
-			if err := m.store.InsertEvents(events); err != nil {
+			writeStart := time.Now()
+			err = m.store.InsertEvents(events)
+			if m.metrics != nil {
+				m.metrics.ObserveWriteLatency(time.Since(writeStart))
+			}
+			if err != nil {
 				fmt.Printf("❌ Storage failed: %v\n", err)
+				if m.metrics != nil {
+					m.metrics.ObserveCollectorError(c.Name())
+				}
 				errChan <- err
 				return
 			}
 
+			if m.metrics != nil {
+				bySeverity := make(map[string]int64)
+				for _, event := range events {
+					bySeverity[event.Severity]++
+				}
+				for severity, n := range bySeverity {
+					m.metrics.ObserveEventsCollected(c.Name(), severity, n)
+				}
+			}
+
 			results <- CollectionResult{
 				Source: c.Name(),
 				Count:  len(events),
@@ -125,9 +158,12 @@ func contains(slice []string, item string) bool {
 package collectors
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
@@ -187,30 +223,96 @@ func (w *WindowsCollector) collectLog(ctx context.Context, logName string, timeR
 	return parseWindowsEvents(output, logName)
 }
 
+// winEventRecord mirrors the fields selected by the Select-Object in
+// collectLog. ConvertTo-Json emits a single object when Get-WinEvent
+// returns exactly one match and an array otherwise, so parseWindowsEvents
+// has to detect which shape it got.
+type winEventRecord struct {
+	TimeCreated      string `json:"TimeCreated"`
+	Id               int    `json:"Id"`
+	LevelDisplayName string `json:"LevelDisplayName"`
+	ProviderName     string `json:"ProviderName"`
+	Message          string `json:"Message"`
+}
+
 func parseWindowsEvents(data []byte, source string) ([]*storage.Event, error) {
-	// Parse JSON output from PowerShell
-	// Simplified - in production use proper JSON parsing
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var records []json.RawMessage
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, fmt.Errorf("unmarshal event array: %w", err)
+		}
+	} else {
+		records = []json.RawMessage{data}
+	}
+
 	var events []*storage.Event
-	
-	lines := strings.Split(string(data), "\n")
-	for _, line := range lines {
-		if strings.TrimSpace(line) == "" {
+	for _, raw := range records {
+		var rec winEventRecord
+		if err := json.Unmarshal(raw, &rec); err != nil {
 			continue
 		}
-		
-		// Basic parsing - replace with proper JSON unmarshal
-		event := &storage.Event{
-			Source:    source,
-			Timestamp: time.Now(), // Parse from JSON
-			Severity:  "INFO",     // Map from LevelDisplayName
-			Message:   line,
+
+		timestamp, err := parsePowerShellTime(rec.TimeCreated)
+		if err != nil {
+			timestamp = time.Now()
 		}
-		events = append(events, event)
+
+		events = append(events, &storage.Event{
+			Source:    source,
+			Timestamp: timestamp,
+			Severity:  mapWindowsSeverity(rec.LevelDisplayName),
+			Message:   rec.Message,
+			EventID:   strconv.Itoa(rec.Id),
+			Metadata: map[string]string{
+				"provider": rec.ProviderName,
+				"level":    rec.LevelDisplayName,
+			},
+		})
 	}
 
 	return events, nil
 }
 
+// mapWindowsSeverity maps Get-WinEvent's LevelDisplayName to the
+// FATAL/ERROR/WARN/INFO/DEBUG scale used throughout the store.
+func mapWindowsSeverity(level string) string {
+	switch strings.ToLower(level) {
+	case "critical":
+		return "FATAL"
+	case "error":
+		return "ERROR"
+	case "warning":
+		return "WARN"
+	case "information":
+		return "INFO"
+	case "verbose":
+		return "DEBUG"
+	default:
+		return "INFO"
+	}
+}
+
+// parsePowerShellTime handles the ISO-8601-with-fractional-seconds format
+// ConvertTo-Json emits for [datetime] values, falling back to plain RFC3339.
+func parsePowerShellTime(s string) (time.Time, error) {
+	layouts := []string{
+		"2006-01-02T15:04:05.9999999",
+		time.RFC3339Nano,
+		time.RFC3339,
+	}
+	for _, layout := range layouts {
+		if ts, err := time.Parse(layout, s); err == nil {
+			return ts, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", s)
+}
+
 // internal/collectors/files.go
 package collectors
 
@@ -410,12 +512,19 @@ func (i *IISCollector) parseIISLog(path string, timeRange config.TimeRange) ([]*
 	defer file.Close()
 
 	var events []*storage.Event
+	var fieldNames []string
 	scanner := bufio.NewScanner(file)
 
 	for scanner.Scan() {
 		line := scanner.Text()
 
-		// Skip comments
+		// The #Fields: directive names every column for the rows that
+		// follow, in order - capture it so field values below can be
+		// stored as metadata keyed by their W3C field name.
+		if strings.HasPrefix(line, "#Fields:") {
+			fieldNames = strings.Fields(strings.TrimPrefix(line, "#Fields:"))
+			continue
+		}
 		if strings.HasPrefix(line, "#") {
 			continue
 		}
@@ -435,15 +544,25 @@ func (i *IISCollector) parseIISLog(path string, timeRange config.TimeRange) ([]*
 			continue
 		}
 
+		metadata := make(map[string]string)
+		if len(fieldNames) == len(fields) {
+			for idx, name := range fieldNames {
+				if fields[idx] != "-" {
+					metadata[name] = fields[idx]
+				}
+			}
+		}
+
 		// Determine severity based on status code
 		severity := "INFO"
-		if len(fields) > 10 {
-			status := fields[10]
-			if strings.HasPrefix(status, "5") {
-				severity = "ERROR"
-			} else if strings.HasPrefix(status, "4") {
-				severity = "WARN"
-			}
+		status := metadata["sc-status"]
+		if status == "" && len(fields) > 10 {
+			status = fields[10]
+		}
+		if strings.HasPrefix(status, "5") {
+			severity = "ERROR"
+		} else if strings.HasPrefix(status, "4") {
+			severity = "WARN"
 		}
 
 		event := &storage.Event{
@@ -451,6 +570,7 @@ func (i *IISCollector) parseIISLog(path string, timeRange config.TimeRange) ([]*
 			Source:    "IIS",
 			Severity:  severity,
 			Message:   line,
+			Metadata:  metadata,
 		}
 		events = append(events, event)
 	}
@@ -0,0 +1,386 @@
+// internal/analyzer/matchers.go
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/antonmedv/expr"
+	"github.com/antonmedv/expr/vm"
+
+	"incident-analyzer/internal/config"
+	"incident-analyzer/internal/storage"
+)
+
+// MatcherMetrics tracks how often a PatternMatcher ran and how long it
+// took, so an operator can see which rules are expensive or noisy.
+type MatcherMetrics struct {
+	Evaluations int64
+	Matches     int64
+	TotalTime   time.Duration
+}
+
+func (m MatcherMetrics) AvgTime() time.Duration {
+	if m.Evaluations == 0 {
+		return 0
+	}
+	return m.TotalTime / time.Duration(m.Evaluations)
+}
+
+// PatternMatcher evaluates one configured pattern against a
+// timestamp-sorted event stream. Matchers are compiled once at engine
+// construction (see newPatternMatchers) so expensive setup - compiling a
+// regex or an expr program - only happens once per process, not once per
+// Analyze call.
+type PatternMatcher interface {
+	Config() config.PatternConfig
+	Evaluate(events []*storage.Event) []PatternMatch
+	Metrics() MatcherMetrics
+}
+
+// newPatternMatchers compiles one PatternMatcher per configured pattern,
+// dispatching on PatternConfig.Type. Type "" falls back to "keyword" so
+// configs written before this field existed keep working unchanged. A
+// pattern that fails to compile (bad regex, bad expr) is dropped with a
+// warning rather than failing engine construction.
+func newPatternMatchers(patterns []config.PatternConfig) []PatternMatcher {
+	matchers := make([]PatternMatcher, 0, len(patterns))
+	for _, p := range patterns {
+		switch p.Type {
+		case "regex":
+			m, err := newRegexMatcher(p)
+			if err != nil {
+				fmt.Printf("⚠️  pattern %q: %v\n", p.Name, err)
+				continue
+			}
+			matchers = append(matchers, m)
+		case "expr":
+			m, err := newExprMatcher(p)
+			if err != nil {
+				fmt.Printf("⚠️  pattern %q: %v\n", p.Name, err)
+				continue
+			}
+			matchers = append(matchers, m)
+		case "sequence":
+			matchers = append(matchers, newSequenceMatcher(p))
+		default:
+			matchers = append(matchers, newKeywordMatcher(p))
+		}
+	}
+	return matchers
+}
+
+// baseMatcher holds the config and metrics counters every matcher
+// implementation embeds; only Evaluate differs between them.
+type baseMatcher struct {
+	cfg            config.PatternConfig
+	evaluations    int64
+	matches        int64
+	totalTimeNanos int64
+}
+
+func (b *baseMatcher) Config() config.PatternConfig { return b.cfg }
+
+func (b *baseMatcher) Metrics() MatcherMetrics {
+	return MatcherMetrics{
+		Evaluations: atomic.LoadInt64(&b.evaluations),
+		Matches:     atomic.LoadInt64(&b.matches),
+		TotalTime:   time.Duration(atomic.LoadInt64(&b.totalTimeNanos)),
+	}
+}
+
+func (b *baseMatcher) observe(start time.Time, matched bool) {
+	atomic.AddInt64(&b.evaluations, 1)
+	if matched {
+		atomic.AddInt64(&b.matches, 1)
+	}
+	atomic.AddInt64(&b.totalTimeNanos, int64(time.Since(start)))
+}
+
+// buildPatternMatch assembles a PatternMatch from a matcher's config and
+// the events it matched; captures is nil for matchers that don't produce
+// any (keyword, expr, sequence).
+func buildPatternMatch(cfg config.PatternConfig, events []*storage.Event, firstSeen, lastSeen time.Time, captures []map[string]string) PatternMatch {
+	return PatternMatch{
+		Name:        cfg.Name,
+		Severity:    cfg.Severity,
+		Description: cfg.Description,
+		Resolution:  cfg.Resolution,
+		Occurrences: len(events),
+		FirstSeen:   firstSeen,
+		LastSeen:    lastSeen,
+		Events:      events,
+		Captures:    captures,
+	}
+}
+
+// keywordMatcher is the original case-insensitive substring match,
+// wrapped in the PatternMatcher interface so it can sit alongside the
+// newer matcher types unchanged in behavior.
+type keywordMatcher struct{ baseMatcher }
+
+func newKeywordMatcher(cfg config.PatternConfig) *keywordMatcher {
+	return &keywordMatcher{baseMatcher{cfg: cfg}}
+}
+
+func (k *keywordMatcher) Evaluate(events []*storage.Event) []PatternMatch {
+	start := time.Now()
+
+	var matched []*storage.Event
+	var firstSeen, lastSeen time.Time
+
+	for _, event := range events {
+		message := strings.ToLower(event.Message)
+		hit := false
+		for _, keyword := range k.cfg.Keywords {
+			if strings.Contains(message, strings.ToLower(keyword)) {
+				hit = true
+				break
+			}
+		}
+		if !hit {
+			continue
+		}
+		matched = append(matched, event)
+		if firstSeen.IsZero() || event.Timestamp.Before(firstSeen) {
+			firstSeen = event.Timestamp
+		}
+		if lastSeen.IsZero() || event.Timestamp.After(lastSeen) {
+			lastSeen = event.Timestamp
+		}
+	}
+
+	k.observe(start, len(matched) > 0)
+	if len(matched) == 0 {
+		return nil
+	}
+	return []PatternMatch{buildPatternMatch(k.cfg, matched, firstSeen, lastSeen, nil)}
+}
+
+// regexMatcher compiles PatternConfig.Regex once and matches it against
+// each event's message, exposing any named capture groups per matched
+// event via PatternMatch.Captures.
+type regexMatcher struct {
+	baseMatcher
+	re *regexp.Regexp
+}
+
+func newRegexMatcher(cfg config.PatternConfig) (*regexMatcher, error) {
+	re, err := regexp.Compile(cfg.Regex)
+	if err != nil {
+		return nil, fmt.Errorf("compile regex: %w", err)
+	}
+	return &regexMatcher{baseMatcher{cfg: cfg}, re}, nil
+}
+
+func (r *regexMatcher) Evaluate(events []*storage.Event) []PatternMatch {
+	start := time.Now()
+
+	var matched []*storage.Event
+	var captures []map[string]string
+	var firstSeen, lastSeen time.Time
+	names := r.re.SubexpNames()
+
+	for _, event := range events {
+		groups := r.re.FindStringSubmatch(event.Message)
+		if groups == nil {
+			continue
+		}
+		matched = append(matched, event)
+		if firstSeen.IsZero() || event.Timestamp.Before(firstSeen) {
+			firstSeen = event.Timestamp
+		}
+		if lastSeen.IsZero() || event.Timestamp.After(lastSeen) {
+			lastSeen = event.Timestamp
+		}
+
+		named := make(map[string]string)
+		for i, name := range names {
+			if name == "" || i >= len(groups) {
+				continue
+			}
+			named[name] = groups[i]
+		}
+		captures = append(captures, named)
+	}
+
+	r.observe(start, len(matched) > 0)
+	if len(matched) == 0 {
+		return nil
+	}
+	return []PatternMatch{buildPatternMatch(r.cfg, matched, firstSeen, lastSeen, captures)}
+}
+
+// exprEvent is the value a PatternConfig.Expr rule sees as `Event`; rules
+// refer to its exported Go names (`Event.Severity`, `Event.Message`, ...),
+// and expr resolves names case-sensitively, so `event.severity` does not
+// compile. Field exposes metadata lookups like `Event.Field("latency")` since expr
+// programs can't reach into a raw Go map with dynamic keys as cleanly as
+// a method call.
+type exprEvent struct {
+	Severity string
+	Message  string
+	Source   string
+	EventID  string
+	metadata map[string]string
+}
+
+func (e exprEvent) Field(key string) string { return e.metadata[key] }
+
+type exprEnv struct {
+	Event exprEvent
+}
+
+// exprMatcher compiles PatternConfig.Expr once via antonmedv/expr, e.g.
+// `Event.Severity == "ERROR" && Event.Message matches "connection.*timeout"`.
+type exprMatcher struct {
+	baseMatcher
+	program *vm.Program
+}
+
+func newExprMatcher(cfg config.PatternConfig) (*exprMatcher, error) {
+	program, err := expr.Compile(cfg.Expr, expr.Env(exprEnv{}), expr.AsBool())
+	if err != nil {
+		return nil, fmt.Errorf("compile expr: %w", err)
+	}
+	return &exprMatcher{baseMatcher{cfg: cfg}, program}, nil
+}
+
+func (m *exprMatcher) Evaluate(events []*storage.Event) []PatternMatch {
+	start := time.Now()
+
+	var matched []*storage.Event
+	var firstSeen, lastSeen time.Time
+
+	for _, event := range events {
+		env := exprEnv{Event: exprEvent{
+			Severity: event.Severity,
+			Message:  event.Message,
+			Source:   event.Source,
+			EventID:  event.EventID,
+			metadata: event.Metadata,
+		}}
+
+		result, err := expr.Run(m.program, env)
+		if err != nil {
+			continue
+		}
+		hit, ok := result.(bool)
+		if !ok || !hit {
+			continue
+		}
+
+		matched = append(matched, event)
+		if firstSeen.IsZero() || event.Timestamp.Before(firstSeen) {
+			firstSeen = event.Timestamp
+		}
+		if lastSeen.IsZero() || event.Timestamp.After(lastSeen) {
+			lastSeen = event.Timestamp
+		}
+	}
+
+	m.observe(start, len(matched) > 0)
+	if len(matched) == 0 {
+		return nil
+	}
+	return []PatternMatch{buildPatternMatch(m.cfg, matched, firstSeen, lastSeen, nil)}
+}
+
+// sequenceMatcher implements "A then B ... within Window, touching at
+// least MinSources sources" as a small state machine over the
+// timestamp-sorted event stream: each in-flight attempt tracks which step
+// it's waiting for next, and is dropped once Window has elapsed since it
+// started without completing.
+type sequenceMatcher struct{ baseMatcher }
+
+func newSequenceMatcher(cfg config.PatternConfig) *sequenceMatcher {
+	return &sequenceMatcher{baseMatcher{cfg: cfg}}
+}
+
+type sequenceAttempt struct {
+	step      int
+	startTime time.Time
+	sources   map[string]bool
+	events    []*storage.Event
+}
+
+func (s *sequenceMatcher) Evaluate(events []*storage.Event) []PatternMatch {
+	start := time.Now()
+
+	steps := s.cfg.Sequence
+	if len(steps) == 0 {
+		s.observe(start, false)
+		return nil
+	}
+
+	window := s.cfg.Window
+	if window <= 0 {
+		window = 30 * time.Second
+	}
+	minSources := s.cfg.MinSources
+	if minSources < 1 {
+		minSources = 1
+	}
+
+	sorted := append([]*storage.Event(nil), events...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	var attempts []*sequenceAttempt
+	var matches []PatternMatch
+
+	complete := func(a *sequenceAttempt) {
+		if len(a.sources) >= minSources {
+			matches = append(matches, buildPatternMatch(s.cfg, a.events, a.events[0].Timestamp, a.events[len(a.events)-1].Timestamp, nil))
+		}
+	}
+
+	for _, event := range sorted {
+		message := strings.ToLower(event.Message)
+
+		var live []*sequenceAttempt
+		for _, a := range attempts {
+			if event.Timestamp.Sub(a.startTime) <= window {
+				live = append(live, a)
+			}
+		}
+		attempts = live
+
+		var advancing []*sequenceAttempt
+		for _, a := range attempts {
+			if !strings.Contains(message, strings.ToLower(steps[a.step])) {
+				advancing = append(advancing, a)
+				continue
+			}
+			a.step++
+			a.sources[event.Source] = true
+			a.events = append(a.events, event)
+			if a.step >= len(steps) {
+				complete(a)
+				continue // finished, don't keep tracking it
+			}
+			advancing = append(advancing, a)
+		}
+		attempts = advancing
+
+		if strings.Contains(message, strings.ToLower(steps[0])) {
+			next := &sequenceAttempt{
+				step:      1,
+				startTime: event.Timestamp,
+				sources:   map[string]bool{event.Source: true},
+				events:    []*storage.Event{event},
+			}
+			if next.step >= len(steps) {
+				complete(next)
+			} else {
+				attempts = append(attempts, next)
+			}
+		}
+	}
+
+	s.observe(start, len(matches) > 0)
+	return matches
+}
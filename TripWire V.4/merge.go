@@ -0,0 +1,57 @@
+// internal/config/merge.go
+package config
+
+// Merge layers overlay onto cfg in place, so a system, user, and project
+// config file can stack and each only needs to specify what it's
+// changing: overlay's non-zero scalar fields win, map entries from
+// overlay are added or replace same-key base entries (base-only entries
+// survive), and overlay's Patterns, if non-empty, replaces cfg's Patterns
+// outright - patterns are an ordered, curated list with no natural key to
+// merge on.
+func (cfg *Config) Merge(overlay *Config) {
+	if overlay == nil {
+		return
+	}
+
+	if overlay.Storage.Driver != "" {
+		cfg.Storage.Driver = overlay.Storage.Driver
+	}
+	if overlay.Storage.DSN != "" {
+		cfg.Storage.DSN = overlay.Storage.DSN
+	}
+
+	if len(overlay.Collectors) > 0 {
+		if cfg.Collectors == nil {
+			cfg.Collectors = make(map[string]CollectorConfig, len(overlay.Collectors))
+		}
+		for name, c := range overlay.Collectors {
+			cfg.Collectors[name] = c
+		}
+	}
+
+	if len(overlay.Patterns) > 0 {
+		cfg.Patterns = overlay.Patterns
+	}
+
+	if overlay.Thresholds.ErrorRateMultiplier != 0 {
+		cfg.Thresholds.ErrorRateMultiplier = overlay.Thresholds.ErrorRateMultiplier
+	}
+	if overlay.Thresholds.AnomalyStdDev != 0 {
+		cfg.Thresholds.AnomalyStdDev = overlay.Thresholds.AnomalyStdDev
+	}
+	if overlay.Thresholds.MinConfidence != 0 {
+		cfg.Thresholds.MinConfidence = overlay.Thresholds.MinConfidence
+	}
+	if overlay.Thresholds.EWMAAlpha != 0 {
+		cfg.Thresholds.EWMAAlpha = overlay.Thresholds.EWMAAlpha
+	}
+
+	if len(overlay.Dependencies) > 0 {
+		if cfg.Dependencies == nil {
+			cfg.Dependencies = make(map[string][]string, len(overlay.Dependencies))
+		}
+		for name, deps := range overlay.Dependencies {
+			cfg.Dependencies[name] = deps
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// internal/metrics/registry.go
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Registry tracks the analyzer's own operational counters so they can be
+// scraped from /metrics in Prometheus text format alongside whatever's
+// ingested via the remote-write endpoint. It has no dependency on
+// storage.Store - these are in-process counters, not the durable metrics
+// table that RemoteWriteHandler writes into.
+type Registry struct {
+	mu              sync.Mutex
+	eventsCollected map[[2]string]int64 // [source, severity] -> count
+	collectorErrors map[string]int64    // source -> count
+
+	writeLatencySumNanos int64 // atomic
+	writeLatencyCount    int64 // atomic
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		eventsCollected: make(map[[2]string]int64),
+		collectorErrors: make(map[string]int64),
+	}
+}
+
+// ObserveEventsCollected adds n to the events_collected_total counter for
+// (source, severity). Call this from Manager.CollectAll/Run as events are
+// stored.
+func (r *Registry) ObserveEventsCollected(source, severity string, n int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.eventsCollected[[2]string{source, severity}] += n
+}
+
+// ObserveCollectorError increments collector_errors_total for source.
+func (r *Registry) ObserveCollectorError(source string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectorErrors[source]++
+}
+
+// ObserveWriteLatency feeds one storage write's duration into
+// storage_write_latency_seconds.
+func (r *Registry) ObserveWriteLatency(d time.Duration) {
+	atomic.AddInt64(&r.writeLatencySumNanos, int64(d))
+	atomic.AddInt64(&r.writeLatencyCount, 1)
+}
+
+// Handler serves the registry's counters in Prometheus text exposition
+// format at GET /metrics.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		r.writeText(w)
+	})
+}
+
+func (r *Registry) writeText(w io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	type eventsRow struct {
+		source, severity string
+		count            int64
+	}
+	var eventsRows []eventsRow
+	for key, count := range r.eventsCollected {
+		eventsRows = append(eventsRows, eventsRow{key[0], key[1], count})
+	}
+	sort.Slice(eventsRows, func(i, j int) bool {
+		if eventsRows[i].source != eventsRows[j].source {
+			return eventsRows[i].source < eventsRows[j].source
+		}
+		return eventsRows[i].severity < eventsRows[j].severity
+	})
+
+	fmt.Fprintln(w, "# HELP events_collected_total Total events stored, by source and severity")
+	fmt.Fprintln(w, "# TYPE events_collected_total counter")
+	for _, row := range eventsRows {
+		fmt.Fprintf(w, "events_collected_total{source=%q,severity=%q} %d\n", row.source, row.severity, row.count)
+	}
+
+	var sources []string
+	for source := range r.collectorErrors {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	fmt.Fprintln(w, "# HELP collector_errors_total Total errors raised by a collector")
+	fmt.Fprintln(w, "# TYPE collector_errors_total counter")
+	for _, source := range sources {
+		fmt.Fprintf(w, "collector_errors_total{source=%q} %d\n", source, r.collectorErrors[source])
+	}
+
+	sum := time.Duration(atomic.LoadInt64(&r.writeLatencySumNanos))
+	count := atomic.LoadInt64(&r.writeLatencyCount)
+
+	fmt.Fprintln(w, "# HELP storage_write_latency_seconds Event batch write latency")
+	fmt.Fprintln(w, "# TYPE storage_write_latency_seconds summary")
+	fmt.Fprintf(w, "storage_write_latency_seconds_sum %f\n", sum.Seconds())
+	fmt.Fprintf(w, "storage_write_latency_seconds_count %d\n", count)
+}
@@ -0,0 +1,75 @@
+// internal/metrics/remotewrite.go
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+
+	"incident-analyzer/internal/storage"
+)
+
+// RemoteWriteHandler serves POST /api/v1/write, accepting a
+// snappy-compressed prompb.WriteRequest (the Prometheus remote_write
+// protocol) and fanning every sample into store.InsertMetric. InsertMetric
+// has no label columns, so the "__name__" label becomes the metric name
+// and every other label is folded into a single "k=v,k=v" source string.
+func RemoteWriteHandler(store storage.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		compressed, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		data, err := snappy.Decode(nil, compressed)
+		if err != nil {
+			http.Error(w, "invalid snappy payload", http.StatusBadRequest)
+			return
+		}
+
+		var writeReq prompb.WriteRequest
+		if err := proto.Unmarshal(data, &writeReq); err != nil {
+			http.Error(w, "invalid protobuf payload", http.StatusBadRequest)
+			return
+		}
+
+		for _, ts := range writeReq.Timeseries {
+			name, source := metricIdentity(ts.Labels)
+			if name == "" {
+				continue
+			}
+			for _, sample := range ts.Samples {
+				sampleTime := time.Unix(0, sample.Timestamp*int64(time.Millisecond))
+				if err := store.InsertMetric(sampleTime, name, sample.Value, source); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+			}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// metricIdentity pulls the __name__ label out as the metric name and joins
+// the rest into a sorted "k=v,k=v" string, so the same label set always
+// produces the same source regardless of label order in the request.
+func metricIdentity(labels []prompb.Label) (name, source string) {
+	var rest []string
+	for _, l := range labels {
+		if l.Name == "__name__" {
+			name = l.Value
+			continue
+		}
+		rest = append(rest, l.Name+"="+l.Value)
+	}
+	sort.Strings(rest)
+	return name, strings.Join(rest, ",")
+}
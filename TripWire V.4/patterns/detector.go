@@ -0,0 +1,285 @@
+// internal/patterns/detector.go
+package patterns
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"incident-analyzer/internal/storage"
+)
+
+const wildcard = "<*>"
+
+const (
+	defaultDepth     = 4
+	defaultThreshold = 0.5
+)
+
+var (
+	ipTokenRegex      = regexp.MustCompile(`^\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}$`)
+	uuidTokenRegex    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	numericTokenRegex = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+)
+
+// Detector mines recurring log templates from event messages using a
+// Drain-style fixed-depth prefix tree: messages are grouped first by token
+// count, then branch on their first `depth` tokens, and within a leaf,
+// messages merge into the same cluster once their token-by-token
+// similarity is at least simThreshold - positions that disagree become
+// <*> in the cluster's template, same as the numeric/UUID/IP tokens that
+// are wildcarded up front.
+//
+// Run against a batch of events after collection (or on a schedule); each
+// resulting cluster is upserted into the patterns table, keyed by its
+// template text (the table has no separate hash column, so the
+// deterministic template string doubles as the stable identifier the
+// request calls for).
+type Detector struct {
+	store     storage.Store
+	depth     int
+	threshold float64
+}
+
+func NewDetector(store storage.Store) *Detector {
+	return &Detector{store: store, depth: defaultDepth, threshold: defaultThreshold}
+}
+
+// cluster is one leaf's running template plus the stats Run needs to
+// upsert into the patterns table.
+type cluster struct {
+	tokens     []string
+	count      int64
+	firstSeen  time.Time
+	lastSeen   time.Time
+	severities map[string]int64
+
+	// seeded is true for a cluster loaded from a prior Run's stored
+	// template (see Run's seeding step) rather than created fresh from
+	// this batch. Its tokens are never widened further - see mergeInto -
+	// so the template string, and therefore the patterns-table row it
+	// upserts into, stays the same across runs instead of drifting into a
+	// new row every time a slightly different value is seen.
+	seeded bool
+	// touched is true once at least one event from this batch has merged
+	// into the cluster. A seeded cluster that no event matches this run
+	// stays untouched and Run skips upserting it, since it has nothing
+	// new to record and its zero-value firstSeen/lastSeen would otherwise
+	// corrupt the stored range.
+	touched bool
+}
+
+// node is one branch point in the prefix tree, keyed by the token seen at
+// this depth. The first token seen at a branch gets its own child; every
+// later distinct token shares a single "<*>" child, so the tree stays
+// shallow instead of growing one path per distinct value.
+type node struct {
+	children map[string]*node
+	clusters []*cluster
+}
+
+// Run mines events into templates and upserts each cluster as one row in
+// the patterns table. Before clustering this batch, Run seeds its prefix
+// tree with every template already in the patterns table (see
+// ListPatternTemplates), so a message that recurs across separate Run
+// calls - e.g. the same incident re-collected on the next schedule -
+// matches the template already on file instead of being clustered fresh
+// and potentially landing in a new, near-duplicate template. Only
+// clusters an event from this batch actually touched are upserted;
+// UpsertPattern is what makes the result durable, widening first/last
+// seen and adding to the existing count each time Run is called again.
+func (d *Detector) Run(events []*storage.Event) error {
+	roots := make(map[int]*node)
+
+	seeds, err := d.store.ListPatternTemplates()
+	if err != nil {
+		return err
+	}
+	for _, p := range seeds {
+		tokens := strings.Fields(p.Name)
+		root, ok := roots[len(tokens)]
+		if !ok {
+			root = &node{children: make(map[string]*node)}
+			roots[len(tokens)] = root
+		}
+		d.seed(root, tokens, p)
+	}
+
+	for _, event := range events {
+		tokens := tokenize(event.Message)
+		root, ok := roots[len(tokens)]
+		if !ok {
+			root = &node{children: make(map[string]*node)}
+			roots[len(tokens)] = root
+		}
+		d.insert(root, tokens, event)
+	}
+
+	for _, root := range roots {
+		for _, c := range collectClusters(root) {
+			if !c.touched {
+				continue
+			}
+			if err := d.upsert(c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// seed places a template loaded from storage into the leaf its own tokens
+// would navigate to, so new events that match it merge via the same
+// bestMatch path a same-run cluster would.
+func (d *Detector) seed(root *node, tokens []string, p *storage.Pattern) {
+	n := d.navigate(root, tokens)
+	n.clusters = append(n.clusters, &cluster{
+		tokens: append([]string(nil), tokens...),
+		// severities records the stored severity with a zero count -
+		// patterns rows don't retain a full per-severity breakdown - so
+		// it never outweighs a severity observed in this run:
+		// dominantSeverity only picks a severity counted above zero, and
+		// a seeded cluster no event touches is not upserted at all.
+		severities: map[string]int64{p.Severity: 0},
+		seeded:     true,
+	})
+}
+
+func (d *Detector) navigate(root *node, tokens []string) *node {
+	n := root
+	for i := 0; i < d.depth && i < len(tokens); i++ {
+		tok := tokens[i]
+		child, ok := n.children[tok]
+		if !ok {
+			if len(n.children) >= 1 {
+				tok = wildcard
+				child, ok = n.children[wildcard]
+			}
+			if !ok {
+				child = &node{children: make(map[string]*node)}
+				n.children[tok] = child
+			}
+		}
+		n = child
+	}
+	return n
+}
+
+func (d *Detector) insert(root *node, tokens []string, event *storage.Event) {
+	n := d.navigate(root, tokens)
+
+	if best := bestMatch(n.clusters, tokens, d.threshold); best != nil {
+		mergeInto(best, tokens, event)
+		return
+	}
+
+	n.clusters = append(n.clusters, &cluster{
+		tokens:     append([]string(nil), tokens...),
+		count:      1,
+		firstSeen:  event.Timestamp,
+		lastSeen:   event.Timestamp,
+		severities: map[string]int64{event.Severity: 1},
+		touched:    true,
+	})
+}
+
+// bestMatch returns the cluster whose template is most similar to tokens,
+// provided it clears threshold; clusters are only ever compared within the
+// same token-count bucket, so similarity is always defined.
+func bestMatch(clusters []*cluster, tokens []string, threshold float64) *cluster {
+	var best *cluster
+	var bestSim float64
+	for _, c := range clusters {
+		sim := similarity(c.tokens, tokens)
+		if sim >= threshold && sim > bestSim {
+			best, bestSim = c, sim
+		}
+	}
+	return best
+}
+
+func similarity(a, b []string) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	matches := 0
+	for i := range a {
+		if a[i] == wildcard || a[i] == b[i] {
+			matches++
+		}
+	}
+	return float64(matches) / float64(len(a))
+}
+
+func mergeInto(c *cluster, tokens []string, event *storage.Event) {
+	// A seeded cluster's tokens come from the template already on file;
+	// widening them here would change the template string Run.upsert
+	// keys on, splitting this run's count off into a new patterns row
+	// instead of adding to the existing one.
+	if !c.seeded {
+		for i := range c.tokens {
+			if c.tokens[i] != wildcard && c.tokens[i] != tokens[i] {
+				c.tokens[i] = wildcard
+			}
+		}
+	}
+	c.count++
+	if !c.touched {
+		c.firstSeen = event.Timestamp
+		c.lastSeen = event.Timestamp
+	} else {
+		if event.Timestamp.Before(c.firstSeen) {
+			c.firstSeen = event.Timestamp
+		}
+		if event.Timestamp.After(c.lastSeen) {
+			c.lastSeen = event.Timestamp
+		}
+	}
+	c.touched = true
+	c.severities[event.Severity]++
+}
+
+func collectClusters(n *node) []*cluster {
+	clusters := append([]*cluster(nil), n.clusters...)
+	for _, child := range n.children {
+		clusters = append(clusters, collectClusters(child)...)
+	}
+	return clusters
+}
+
+func (d *Detector) upsert(c *cluster) error {
+	template := strings.Join(c.tokens, " ")
+	return d.store.UpsertPattern(template, c.firstSeen, c.lastSeen, dominantSeverity(c.severities), c.count)
+}
+
+func dominantSeverity(counts map[string]int64) string {
+	var best string
+	var bestCount int64
+	for severity, n := range counts {
+		if n > bestCount {
+			best, bestCount = severity, n
+		}
+	}
+	return best
+}
+
+// tokenize splits message on whitespace and replaces numeric/UUID/IP
+// tokens with <*> so that, e.g., "retry 3 of 5" and "retry 7 of 5" land in
+// the same cluster from the start instead of needing a merge.
+func tokenize(message string) []string {
+	fields := strings.Fields(message)
+	tokens := make([]string, len(fields))
+	for i, tok := range fields {
+		tokens[i] = normalizeToken(tok)
+	}
+	return tokens
+}
+
+func normalizeToken(tok string) string {
+	switch {
+	case ipTokenRegex.MatchString(tok), uuidTokenRegex.MatchString(tok), numericTokenRegex.MatchString(tok):
+		return wildcard
+	default:
+		return tok
+	}
+}
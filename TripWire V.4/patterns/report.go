@@ -0,0 +1,52 @@
+// internal/patterns/report.go
+package patterns
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"incident-analyzer/internal/storage"
+)
+
+// FormatTopPatterns renders the top N pattern clusters in [start, end] and
+// their severity mix, in the same terminal report style as
+// reporters.generateTerminalReport.
+//
+// NOTE: this only formats the string; no `analyze patterns` CLI
+// subcommand wires it up to os.Args yet, since this tree has no main/CLI
+// package at all to hang one off of. Whatever eventually adds one should
+// call this rather than re-deriving the rendering.
+func FormatTopPatterns(store storage.Store, start, end time.Time, limit int) (string, error) {
+	top, err := store.GetTopPatterns(start, end, limit)
+	if err != nil {
+		return "", fmt.Errorf("get top patterns: %w", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "🔍 TOP %d PATTERNS (%s - %s)\n", limit, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	fmt.Fprintln(&b, strings.Repeat("━", 70))
+
+	if len(top) == 0 {
+		fmt.Fprintln(&b, "No recurring patterns found in this range")
+		fmt.Fprintln(&b)
+		return b.String(), nil
+	}
+
+	for i, p := range top {
+		icon := "  "
+		switch p.Severity {
+		case "FATAL", "CRITICAL":
+			icon = "🔴"
+		case "ERROR":
+			icon = "🟠"
+		case "WARN", "WARNING":
+			icon = "🟡"
+		}
+		fmt.Fprintf(&b, "%2d. %s [%5dx] %s\n", i+1, icon, p.Count, p.Name)
+	}
+	fmt.Fprintln(&b)
+
+	return b.String(), nil
+}
@@ -0,0 +1,125 @@
+// internal/storage/patterns_store.go
+package storage
+
+import (
+	"strings"
+	"time"
+)
+
+// UpsertPattern records count more occurrences of the template name,
+// widening the [firstSeen, lastSeen] range and overwriting severity with
+// whatever this run observed most recently. name must be unique per
+// template (idx_patterns_name enforces it) - internal/patterns passes the
+// clustered template text itself (e.g. "user <*> logged in"), not the raw
+// message.
+func (s *SQLiteStore) UpsertPattern(name string, firstSeen, lastSeen time.Time, severity string, count int64) error {
+	_, err := s.db.Exec(`
+		INSERT INTO patterns (pattern_name, first_seen, last_seen, count, severity)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(pattern_name) DO UPDATE SET
+			first_seen = MIN(first_seen, excluded.first_seen),
+			last_seen = MAX(last_seen, excluded.last_seen),
+			count = count + excluded.count,
+			severity = excluded.severity
+	`, name, firstSeen, lastSeen, count, severity)
+	return err
+}
+
+// GetTopPatterns returns the templates with the most occurrences whose
+// last_seen falls within [start, end], most frequent first.
+func (s *SQLiteStore) GetTopPatterns(start, end time.Time, limit int) ([]*Pattern, error) {
+	rows, err := s.readDB.Query(`
+		SELECT id, pattern_name, first_seen, last_seen, count, severity
+		FROM patterns
+		WHERE last_seen BETWEEN ? AND ?
+		ORDER BY count DESC
+		LIMIT ?
+	`, start, end, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var patterns []*Pattern
+	for rows.Next() {
+		p := &Pattern{}
+		if err := rows.Scan(&p.ID, &p.Name, &p.FirstSeen, &p.LastSeen, &p.Count, &p.Severity); err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, rows.Err()
+}
+
+// ListPatternTemplates returns every previously mined template, with no
+// time-range or count filtering - internal/patterns.Detector uses this to
+// seed a new Run's clustering with prior runs' templates, so the same
+// recurring incident doesn't fragment into a different cluster just
+// because it's being re-mined from scratch each time.
+func (s *SQLiteStore) ListPatternTemplates() ([]*Pattern, error) {
+	rows, err := s.readDB.Query(`
+		SELECT id, pattern_name, first_seen, last_seen, count, severity
+		FROM patterns
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var patterns []*Pattern
+	for rows.Next() {
+		p := &Pattern{}
+		if err := rows.Scan(&p.ID, &p.Name, &p.FirstSeen, &p.LastSeen, &p.Count, &p.Severity); err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, rows.Err()
+}
+
+// GetPatternEvents returns events whose message matches patternID's
+// template. The template's <*> wildcards become SQL LIKE wildcards; this
+// is an approximation (a LIKE scan, not the tokenizer the template was
+// mined with) but is good enough to show a cluster's member events.
+func (s *SQLiteStore) GetPatternEvents(patternID int64, limit int) ([]*Event, error) {
+	var name string
+	var firstSeen, lastSeen time.Time
+	err := s.readDB.QueryRow(`
+		SELECT pattern_name, first_seen, last_seen FROM patterns WHERE id = ?
+	`, patternID).Scan(&name, &firstSeen, &lastSeen)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.readDB.Query(`
+		SELECT id, timestamp, source, severity, message, event_id, raw_data
+		FROM events
+		WHERE timestamp BETWEEN ? AND ?
+		AND message LIKE ? ESCAPE '\'
+		ORDER BY timestamp DESC
+		LIMIT ?
+	`, firstSeen, lastSeen, templateToLikePattern(name), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*Event
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// templateToLikePattern turns a mined template such as "user <*> logged in
+// from <*>" into a SQL LIKE pattern ("user % logged in from %"), escaping
+// any literal % or _ in the non-wildcard parts so they aren't mistaken for
+// LIKE metacharacters.
+func templateToLikePattern(template string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(template)
+	return strings.ReplaceAll(escaped, "<*>", "%")
+}
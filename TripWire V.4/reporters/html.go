@@ -0,0 +1,234 @@
+// internal/reporters/html.go
+package reporters
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"incident-analyzer/internal/analyzer"
+)
+
+//go:embed assets/report.css assets/report.js
+var htmlAssets embed.FS
+
+func init() {
+	Register("html", htmlReporter{})
+}
+
+// HTMLOptions controls how much of an AnalysisResult the interactive HTML
+// reporter embeds alongside the page it renders, so report.html's size
+// scales with what's actually embedded rather than always carrying full
+// per-event detail for a large incident.
+type HTMLOptions struct {
+	// IncludeRawEvents embeds each matched pattern's underlying events
+	// (timestamp/source/severity/message) so the client-side swimlane view
+	// can place per-event markers; without it, only each pattern's
+	// aggregate FirstSeen/LastSeen/Occurrences is available client-side.
+	IncludeRawEvents bool
+	// MaxEvents caps the total number of raw events embedded across all
+	// patterns when IncludeRawEvents is set. The budget is spent pattern by
+	// pattern in result order, keeping each pattern's most recent events,
+	// so later patterns may get none. Zero means no cap.
+	MaxEvents int
+}
+
+// DefaultHTMLOptions is what the html Reporter registered via Register
+// uses: summary, pattern, and error data, no raw per-event detail. Use
+// Manager.GenerateHTMLWithOptions for anything else.
+func DefaultHTMLOptions() HTMLOptions {
+	return HTMLOptions{}
+}
+
+// htmlReporter renders result as a single self-contained HTML file: the
+// full report data as an embedded JSON blob plus the assets/report.js
+// bundle that renders it client-side. No CDN dependency, so the file
+// stays usable offline on an air-gapped incident-response machine.
+type htmlReporter struct{}
+
+func (htmlReporter) Name() string { return "html" }
+
+func (htmlReporter) DefaultExtension() string { return "html" }
+
+func (htmlReporter) Render(result *analyzer.AnalysisResult, dest io.Writer) error {
+	return renderInteractiveHTML(result, dest, DefaultHTMLOptions())
+}
+
+func renderInteractiveHTML(result *analyzer.AnalysisResult, dest io.Writer, opts HTMLOptions) error {
+	data, err := json.Marshal(buildHTMLPayload(result, opts))
+	if err != nil {
+		return fmt.Errorf("encode report payload: %w", err)
+	}
+
+	css, err := htmlAssets.ReadFile("assets/report.css")
+	if err != nil {
+		return fmt.Errorf("read report.css: %w", err)
+	}
+	js, err := htmlAssets.ReadFile("assets/report.js")
+	if err != nil {
+		return fmt.Errorf("read report.js: %w", err)
+	}
+
+	fmt.Fprintf(dest, `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8">
+<title>Incident Analysis Report</title>
+<style>
+%s
+</style>
+</head>
+<body>
+<div id="app"></div>
+<script id="report-data" type="application/json">%s</script>
+<script>
+%s
+</script>
+</body>
+</html>
+`, css, data, js)
+
+	return nil
+}
+
+// htmlPayload is the JSON blob embedded in report.html for assets/report.js
+// to render - its own shape rather than a reuse of jsonReport, since this
+// one also carries chart and swimlane data the json Reporter has no
+// reason to.
+type htmlPayload struct {
+	Summary         htmlSummary    `json:"summary"`
+	RootCause       *htmlRootCause `json:"root_cause,omitempty"`
+	CascadeDetected bool           `json:"cascade_detected"`
+	Recommendations []string       `json:"recommendations,omitempty"`
+	Patterns        []htmlPattern  `json:"patterns,omitempty"`
+	TimeSeries      []htmlPoint    `json:"time_series,omitempty"`
+	TopErrors       []htmlTopError `json:"top_errors,omitempty"`
+}
+
+type htmlSummary struct {
+	TotalEvents     int64    `json:"total_events"`
+	ErrorCount      int64    `json:"error_count"`
+	WarningCount    int64    `json:"warning_count"`
+	ErrorRate       float64  `json:"error_rate"`
+	Duration        string   `json:"duration"`
+	AffectedSources []string `json:"affected_sources,omitempty"`
+}
+
+type htmlRootCause struct {
+	Pattern     string   `json:"pattern"`
+	Confidence  float64  `json:"confidence"`
+	Timestamp   string   `json:"timestamp"`
+	Description string   `json:"description"`
+	Resolution  string   `json:"resolution"`
+	Evidence    []string `json:"evidence,omitempty"`
+}
+
+type htmlPattern struct {
+	Name        string      `json:"name"`
+	Severity    string      `json:"severity"`
+	Occurrences int         `json:"occurrences"`
+	FirstSeen   string      `json:"first_seen"`
+	LastSeen    string      `json:"last_seen"`
+	Events      []htmlEvent `json:"events,omitempty"`
+}
+
+type htmlEvent struct {
+	Timestamp string `json:"timestamp"`
+	Source    string `json:"source"`
+	Severity  string `json:"severity"`
+	Message   string `json:"message"`
+}
+
+type htmlPoint struct {
+	Timestamp string `json:"timestamp"`
+	Total     int64  `json:"total"`
+	Errors    int64  `json:"errors"`
+}
+
+type htmlTopError struct {
+	Message  string   `json:"message"`
+	Count    int      `json:"count"`
+	Severity string   `json:"severity"`
+	Sources  []string `json:"sources,omitempty"`
+}
+
+func buildHTMLPayload(result *analyzer.AnalysisResult, opts HTMLOptions) htmlPayload {
+	payload := htmlPayload{
+		Summary: htmlSummary{
+			TotalEvents:     result.Summary.TotalEvents,
+			ErrorCount:      result.Summary.ErrorCount,
+			WarningCount:    result.Summary.WarningCount,
+			ErrorRate:       result.Summary.ErrorRate,
+			Duration:        result.Summary.Duration.String(),
+			AffectedSources: result.Summary.AffectedSources,
+		},
+		CascadeDetected: result.CascadeDetected,
+		Recommendations: result.Recommendations,
+	}
+
+	if result.RootCause != nil {
+		payload.RootCause = &htmlRootCause{
+			Pattern:     result.RootCause.Pattern,
+			Confidence:  result.RootCause.Confidence,
+			Timestamp:   result.RootCause.Timestamp.Format(time.RFC3339),
+			Description: result.RootCause.Description,
+			Resolution:  result.RootCause.Resolution,
+			Evidence:    result.RootCause.Evidence,
+		}
+	}
+
+	// remaining tracks the budget across patterns, most recent events
+	// within each pattern first, so MaxEvents caps the total embedded
+	// rather than allowing MaxEvents per pattern.
+	remaining := opts.MaxEvents
+	for _, p := range result.Patterns {
+		hp := htmlPattern{
+			Name:        p.Name,
+			Severity:    p.Severity,
+			Occurrences: p.Occurrences,
+			FirstSeen:   p.FirstSeen.Format(time.RFC3339),
+			LastSeen:    p.LastSeen.Format(time.RFC3339),
+		}
+		if opts.IncludeRawEvents {
+			events := p.Events
+			if opts.MaxEvents > 0 {
+				if remaining <= 0 {
+					events = nil
+				} else if len(events) > remaining {
+					events = events[len(events)-remaining:]
+				}
+				remaining -= len(events)
+			}
+			for _, e := range events {
+				hp.Events = append(hp.Events, htmlEvent{
+					Timestamp: e.Timestamp.Format(time.RFC3339),
+					Source:    e.Source,
+					Severity:  e.Severity,
+					Message:   e.Message,
+				})
+			}
+		}
+		payload.Patterns = append(payload.Patterns, hp)
+	}
+
+	for _, point := range result.Statistics.TimeSeries {
+		payload.TimeSeries = append(payload.TimeSeries, htmlPoint{
+			Timestamp: point.Timestamp.Format(time.RFC3339),
+			Total:     point.Total,
+			Errors:    point.Errors,
+		})
+	}
+
+	for _, e := range result.Statistics.TopErrors {
+		payload.TopErrors = append(payload.TopErrors, htmlTopError{
+			Message:  e.Message,
+			Count:    e.Count,
+			Severity: "ERROR",
+			Sources:  e.Sources,
+		})
+	}
+
+	return payload
+}
@@ -0,0 +1,117 @@
+// internal/reporters/json.go
+package reporters
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"incident-analyzer/internal/analyzer"
+)
+
+func init() {
+	Register("json", jsonReporter{})
+}
+
+// jsonSchemaVersion is bumped whenever a field is removed or its meaning
+// changes; additive fields don't require a bump. Consumers diffing output
+// across runs should key on this rather than assuming shape.
+const jsonSchemaVersion = 1
+
+type jsonReporter struct{}
+
+func (jsonReporter) Name() string { return "json" }
+
+func (jsonReporter) DefaultExtension() string { return "json" }
+
+func (jsonReporter) Render(result *analyzer.AnalysisResult, dest io.Writer) error {
+	report := jsonReport{
+		SchemaVersion:   jsonSchemaVersion,
+		TimeRange:       jsonTimeRange{Start: result.Summary.TimeRange.Start, End: result.Summary.TimeRange.End},
+		Summary:         jsonSummaryOf(result),
+		CascadeDetected: result.CascadeDetected,
+		Recommendations: result.Recommendations,
+		OccurrenceCount: result.OccurrenceCount,
+	}
+	for _, p := range result.Patterns {
+		report.Patterns = append(report.Patterns, jsonPattern{
+			Name:        p.Name,
+			Severity:    p.Severity,
+			Description: p.Description,
+			Occurrences: p.Occurrences,
+			FirstSeen:   p.FirstSeen,
+			LastSeen:    p.LastSeen,
+		})
+	}
+	if result.RootCause != nil {
+		report.RootCause = &jsonRootCause{
+			Pattern:     result.RootCause.Pattern,
+			Confidence:  result.RootCause.Confidence,
+			Timestamp:   result.RootCause.Timestamp,
+			Description: result.RootCause.Description,
+			Evidence:    result.RootCause.Evidence,
+			Resolution:  result.RootCause.Resolution,
+		}
+	}
+
+	enc := json.NewEncoder(dest)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// jsonReport is the stable, versioned shape of the JSON reporter's output -
+// deliberately its own type rather than a direct marshal of
+// analyzer.AnalysisResult, so internal engine fields can change without
+// breaking whatever is diffing this output across runs (e.g. a CI gate
+// comparing successive analyses).
+type jsonReport struct {
+	SchemaVersion   int            `json:"schema_version"`
+	TimeRange       jsonTimeRange  `json:"time_range"`
+	Summary         jsonSummary    `json:"summary"`
+	Patterns        []jsonPattern  `json:"patterns,omitempty"`
+	RootCause       *jsonRootCause `json:"root_cause,omitempty"`
+	CascadeDetected bool           `json:"cascade_detected"`
+	Recommendations []string       `json:"recommendations,omitempty"`
+	OccurrenceCount int            `json:"occurrence_count,omitempty"`
+}
+
+type jsonTimeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type jsonSummary struct {
+	TotalEvents     int64    `json:"total_events"`
+	ErrorCount      int64    `json:"error_count"`
+	WarningCount    int64    `json:"warning_count"`
+	ErrorRate       float64  `json:"error_rate"`
+	AffectedSources []string `json:"affected_sources,omitempty"`
+}
+
+func jsonSummaryOf(result *analyzer.AnalysisResult) jsonSummary {
+	return jsonSummary{
+		TotalEvents:     result.Summary.TotalEvents,
+		ErrorCount:      result.Summary.ErrorCount,
+		WarningCount:    result.Summary.WarningCount,
+		ErrorRate:       result.Summary.ErrorRate,
+		AffectedSources: result.Summary.AffectedSources,
+	}
+}
+
+type jsonPattern struct {
+	Name        string    `json:"name"`
+	Severity    string    `json:"severity"`
+	Description string    `json:"description"`
+	Occurrences int       `json:"occurrences"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+type jsonRootCause struct {
+	Pattern     string    `json:"pattern"`
+	Confidence  float64   `json:"confidence"`
+	Timestamp   time.Time `json:"timestamp"`
+	Description string    `json:"description"`
+	Evidence    []string  `json:"evidence,omitempty"`
+	Resolution  string    `json:"resolution,omitempty"`
+}
@@ -0,0 +1,120 @@
+// internal/reporters/markdown.go
+package reporters
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"incident-analyzer/internal/analyzer"
+)
+
+func init() {
+	Register("markdown", markdownReporter{})
+}
+
+type markdownReporter struct{}
+
+func (markdownReporter) Name() string { return "markdown" }
+
+func (markdownReporter) DefaultExtension() string { return "md" }
+
+func (markdownReporter) Render(result *analyzer.AnalysisResult, dest io.Writer) error {
+	return generateMarkdownReport(result, dest)
+}
+
+func generateMarkdownReport(result *analyzer.AnalysisResult, f io.Writer) error {
+	fmt.Fprintf(f, "# Incident Analysis Report\n\n")
+	fmt.Fprintf(f, "**Generated:** %s\n\n", result.Summary.TimeRange.End.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(f, "**Time Window:** %s → %s (%s)\n\n",
+		result.Summary.TimeRange.Start.Format("2006-01-02 15:04"),
+		result.Summary.TimeRange.End.Format("2006-01-02 15:04"),
+		result.Summary.Duration)
+
+	fmt.Fprintf(f, "---\n\n")
+
+	// Root Cause
+	if result.RootCause != nil {
+		fmt.Fprintf(f, "## 🚨 Root Cause\n\n")
+		fmt.Fprintf(f, "**Pattern:** %s  \n", result.RootCause.Pattern)
+		fmt.Fprintf(f, "**Confidence:** %.0f%%  \n", result.RootCause.Confidence*100)
+		fmt.Fprintf(f, "**First Detected:** %s  \n\n", result.RootCause.Timestamp.Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(f, "**Description:** %s\n\n", result.RootCause.Description)
+
+		if len(result.RootCause.Evidence) > 0 {
+			fmt.Fprintf(f, "### Evidence\n\n")
+			for _, evidence := range result.RootCause.Evidence {
+				fmt.Fprintf(f, "- %s\n", evidence)
+			}
+			fmt.Fprintf(f, "\n")
+		}
+
+		fmt.Fprintf(f, "### Resolution Steps\n\n")
+		fmt.Fprintf(f, "```\n%s\n```\n\n", result.RootCause.Resolution)
+	}
+
+	// Summary
+	fmt.Fprintf(f, "## 📊 Summary\n\n")
+	fmt.Fprintf(f, "| Metric | Value |\n")
+	fmt.Fprintf(f, "|--------|-------|\n")
+	fmt.Fprintf(f, "| Total Events | %d |\n", result.Summary.TotalEvents)
+	fmt.Fprintf(f, "| Errors | %d (%.1f%%) |\n", result.Summary.ErrorCount, result.Summary.ErrorRate)
+	fmt.Fprintf(f, "| Warnings | %d |\n", result.Summary.WarningCount)
+	fmt.Fprintf(f, "| Duration | %s |\n", result.Summary.Duration)
+	fmt.Fprintf(f, "\n")
+
+	// Patterns
+	if len(result.Patterns) > 0 {
+		fmt.Fprintf(f, "## 🔍 Detected Patterns\n\n")
+		fmt.Fprintf(f, "| Pattern | Severity | Occurrences | First Seen | Last Seen |\n")
+		fmt.Fprintf(f, "|---------|----------|-------------|------------|----------|\n")
+		for _, pattern := range result.Patterns {
+			fmt.Fprintf(f, "| %s | %s | %d | %s | %s |\n",
+				pattern.Name,
+				pattern.Severity,
+				pattern.Occurrences,
+				pattern.FirstSeen.Format("15:04:05"),
+				pattern.LastSeen.Format("15:04:05"))
+		}
+		fmt.Fprintf(f, "\n")
+	}
+
+	// Anomalies
+	if len(result.Anomalies) > 0 {
+		fmt.Fprintf(f, "## ⚠️ Anomalies\n\n")
+		for _, anomaly := range result.Anomalies {
+			fmt.Fprintf(f, "### %s\n\n", anomaly.Type)
+			fmt.Fprintf(f, "%s\n\n", anomaly.Description)
+		}
+	}
+
+	// Top Errors
+	if len(result.Statistics.TopErrors) > 0 {
+		fmt.Fprintf(f, "## 🔴 Top Errors\n\n")
+		fmt.Fprintf(f, "| Count | Message | Sources |\n")
+		fmt.Fprintf(f, "|-------|---------|----------|\n")
+		for _, err := range result.Statistics.TopErrors {
+			fmt.Fprintf(f, "| %d | %s | %s |\n",
+				err.Count,
+				err.Message,
+				strings.Join(err.Sources, ", "))
+		}
+		fmt.Fprintf(f, "\n")
+	}
+
+	// Recommendations
+	if len(result.Recommendations) > 0 {
+		fmt.Fprintf(f, "## 📋 Recommendations\n\n")
+		for _, rec := range result.Recommendations {
+			if rec == "" {
+				fmt.Fprintf(f, "\n")
+			} else if strings.HasPrefix(rec, " ") {
+				fmt.Fprintf(f, "%s\n", rec)
+			} else {
+				fmt.Fprintf(f, "**%s**\n\n", rec)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+// internal/reporters/reporter.go
+package reporters
+
+import (
+	"fmt"
+	"io"
+
+	"incident-analyzer/internal/analyzer"
+)
+
+// Reporter renders an AnalysisResult in one output format. Built-in
+// reporters (terminal, markdown, html, json, sarif) register themselves
+// from an init(); third parties can add new formats the same way without
+// touching this package.
+type Reporter interface {
+	Name() string
+	Render(result *analyzer.AnalysisResult, dest io.Writer) error
+	DefaultExtension() string
+}
+
+var registry = make(map[string]Reporter)
+
+// Register makes a Reporter available under name (e.g. "json", "sarif").
+// Called from each built-in reporter's init(), mirroring the
+// storage.RegisterDriver pattern.
+func Register(name string, r Reporter) {
+	registry[name] = r
+}
+
+// lookup returns the Reporter registered under name.
+func lookup(name string) (Reporter, error) {
+	r, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("reporters: unknown format %q", name)
+	}
+	return r, nil
+}
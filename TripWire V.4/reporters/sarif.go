@@ -0,0 +1,161 @@
+// internal/reporters/sarif.go
+package reporters
+
+import (
+	"encoding/json"
+	"io"
+
+	"incident-analyzer/internal/analyzer"
+)
+
+func init() {
+	Register("sarif", sarifReporter{})
+}
+
+const sarifVersion = "2.1.0"
+const sarifSchemaURI = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+
+// sarifReporter renders an AnalysisResult as a SARIF 2.1.0 log so GitHub
+// code scanning and other SARIF-aware CIs can ingest incident findings
+// directly: one rule and one result per detected pattern, plus one result
+// for the root cause.
+type sarifReporter struct{}
+
+func (sarifReporter) Name() string { return "sarif" }
+
+func (sarifReporter) DefaultExtension() string { return "sarif" }
+
+func (sarifReporter) Render(result *analyzer.AnalysisResult, dest io.Writer) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name: "incident-analyzer",
+			},
+		},
+	}
+
+	locations := artifactLocations(result.Summary.AffectedSources)
+
+	for _, p := range result.Patterns {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:                   p.Name,
+			ShortDescription:     sarifMessage{Text: p.Description},
+			DefaultConfiguration: sarifRuleConfig{Level: sarifLevel(p.Severity)},
+		})
+
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    p.Name,
+			Level:     sarifLevel(p.Severity),
+			Message:   sarifMessage{Text: p.Description},
+			Locations: locations,
+			PartialFingerprints: map[string]string{
+				"patternName/v1": p.Name,
+				"firstSeen/v1":   p.FirstSeen.Format("2006-01-02T15:04:05Z07:00"),
+			},
+		})
+	}
+
+	if rc := result.RootCause; rc != nil {
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    rc.Pattern,
+			Level:     "error",
+			Message:   sarifMessage{Text: rc.Description},
+			Locations: locations,
+			PartialFingerprints: map[string]string{
+				"patternName/v1": rc.Pattern,
+				"firstSeen/v1":   rc.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
+			},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchemaURI,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+
+	enc := json.NewEncoder(dest)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// sarifLevel maps a PatternMatch severity to a SARIF rule/result level
+// (the root cause's result is always "error"): CRITICAL findings block a CI the way an "error" would, HIGH
+// findings warrant a look but not a failure, and everything else is
+// informational.
+func sarifLevel(severity string) string {
+	switch severity {
+	case "CRITICAL":
+		return "error"
+	case "HIGH":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+func artifactLocations(sources []string) []sarifLocation {
+	locations := make([]sarifLocation, 0, len(sources))
+	for _, source := range sources {
+		locations = append(locations, sarifLocation{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: source},
+			},
+		})
+	}
+	return locations
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID                   string          `json:"id"`
+	ShortDescription     sarifMessage    `json:"shortDescription"`
+	DefaultConfiguration sarifRuleConfig `json:"defaultConfiguration"`
+}
+
+type sarifRuleConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
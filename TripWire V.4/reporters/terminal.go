@@ -0,0 +1,111 @@
+// internal/reporters/terminal.go
+package reporters
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"incident-analyzer/internal/analyzer"
+)
+
+func init() {
+	Register("terminal", terminalReporter{})
+}
+
+type terminalReporter struct{}
+
+func (terminalReporter) Name() string { return "terminal" }
+
+func (terminalReporter) DefaultExtension() string { return "txt" }
+
+func (terminalReporter) Render(result *analyzer.AnalysisResult, dest io.Writer) error {
+	return generateTerminalReport(result, dest)
+}
+
+func generateTerminalReport(result *analyzer.AnalysisResult, w io.Writer) error {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, strings.Repeat("━", 70))
+
+	if result.RootCause != nil {
+		fmt.Fprintln(w, "🚨 ROOT CAUSE IDENTIFIED")
+		fmt.Fprintln(w, strings.Repeat("━", 70))
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Pattern: %s\n", result.RootCause.Pattern)
+		fmt.Fprintf(w, "Confidence: %.0f%%\n", result.RootCause.Confidence*100)
+		fmt.Fprintf(w, "First Detected: %s\n", result.RootCause.Timestamp.Format("2006-01-02 15:04:05"))
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Description: %s\n", result.RootCause.Description)
+		fmt.Fprintln(w)
+
+		if len(result.RootCause.Evidence) > 0 {
+			fmt.Fprintln(w, "Evidence:")
+			for _, evidence := range result.RootCause.Evidence {
+				fmt.Fprintf(w, "  • %s\n", evidence)
+			}
+			fmt.Fprintln(w)
+		}
+	} else {
+		fmt.Fprintln(w, "ℹ️  ANALYSIS SUMMARY")
+		fmt.Fprintln(w, strings.Repeat("━", 70))
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "No definitive root cause identified")
+		fmt.Fprintln(w)
+	}
+
+	// Summary statistics
+	fmt.Fprintln(w, "📊 SUMMARY")
+	fmt.Fprintln(w, strings.Repeat("━", 70))
+	fmt.Fprintf(w, "Total Events: %d\n", result.Summary.TotalEvents)
+	fmt.Fprintf(w, "Errors: %d (%.1f%%)\n", result.Summary.ErrorCount, result.Summary.ErrorRate)
+	fmt.Fprintf(w, "Warnings: %d\n", result.Summary.WarningCount)
+	fmt.Fprintf(w, "Duration: %s\n", result.Summary.Duration)
+
+	if len(result.Summary.AffectedSources) > 0 {
+		fmt.Fprintf(w, "Affected Sources: %s\n", strings.Join(result.Summary.AffectedSources, ", "))
+	}
+	fmt.Fprintln(w)
+
+	// Patterns
+	if len(result.Patterns) > 0 {
+		fmt.Fprintln(w, "🔍 DETECTED PATTERNS")
+		fmt.Fprintln(w, strings.Repeat("━", 70))
+		for _, pattern := range result.Patterns {
+			severity := "  "
+			if pattern.Severity == "CRITICAL" {
+				severity = "🔴"
+			} else if pattern.Severity == "HIGH" {
+				severity = "🟡"
+			}
+			fmt.Fprintf(w, "%s %-40s (%d occurrences)\n", severity, pattern.Name, pattern.Occurrences)
+			fmt.Fprintf(w, "   First: %s | Last: %s\n",
+				pattern.FirstSeen.Format("15:04:05"),
+				pattern.LastSeen.Format("15:04:05"))
+		}
+		fmt.Fprintln(w)
+	}
+
+	// Anomalies
+	if len(result.Anomalies) > 0 {
+		fmt.Fprintln(w, "⚠️  ANOMALIES DETECTED")
+		fmt.Fprintln(w, strings.Repeat("━", 70))
+		for _, anomaly := range result.Anomalies {
+			fmt.Fprintf(w, "• %s: %s\n", anomaly.Type, anomaly.Description)
+		}
+		fmt.Fprintln(w)
+	}
+
+	// Recommendations
+	if len(result.Recommendations) > 0 {
+		fmt.Fprintln(w, "📋 RECOMMENDATIONS")
+		fmt.Fprintln(w, strings.Repeat("━", 70))
+		for _, rec := range result.Recommendations {
+			fmt.Fprintln(w, rec)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, strings.Repeat("━", 70))
+
+	return nil
+}
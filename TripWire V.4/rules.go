@@ -0,0 +1,197 @@
+// internal/analyzer/rules.go
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+
+	"incident-analyzer/internal/config"
+	"incident-analyzer/internal/storage"
+)
+
+// Rule is a compiled PatternConfig.Regexes/When/Cooldown/MinOccurrences -
+// a refinement layered on top of whatever PatternMatcher already matched
+// a pattern's Type, not a replacement for it. RuleSet.apply narrows a
+// matcher's result down to events also satisfying Regexes/When, then
+// gates the narrowed result through Cooldown/MinOccurrences before it's
+// allowed to surface.
+type Rule struct {
+	cfg     config.PatternConfig
+	regexes []*regexp.Regexp
+	program cel.Program
+
+	mu        sync.Mutex
+	lastMatch time.Time
+}
+
+// matches reports whether event satisfies rule's Regexes (if any, OR'd
+// together) and When (if set) - both must pass when both are configured.
+func (r *Rule) matches(event *storage.Event) (bool, error) {
+	if len(r.regexes) > 0 {
+		hit := false
+		for _, re := range r.regexes {
+			if re.MatchString(event.Message) {
+				hit = true
+				break
+			}
+		}
+		if !hit {
+			return false, nil
+		}
+	}
+
+	if r.program == nil {
+		return true, nil
+	}
+
+	out, _, err := r.program.Eval(map[string]interface{}{
+		"source":    event.Source,
+		"severity":  event.Severity,
+		"timestamp": event.Timestamp,
+		"fields":    event.Metadata,
+	})
+	if err != nil {
+		return false, err
+	}
+	hit, ok := out.Value().(bool)
+	return ok && hit, nil
+}
+
+// gate applies Cooldown and MinOccurrences to a candidate occurrence of
+// occurrences events, most recently at matchTime: it reports whether the
+// pattern should actually surface, and if so records matchTime as the
+// rule's last surfaced occurrence for the next Cooldown check.
+func (r *Rule) gate(occurrences int, matchTime time.Time) bool {
+	if r.cfg.MinOccurrences > 0 && occurrences < r.cfg.MinOccurrences {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cfg.Cooldown > 0 && !r.lastMatch.IsZero() && matchTime.Sub(r.lastMatch) < r.cfg.Cooldown {
+		return false
+	}
+	r.lastMatch = matchTime
+	return true
+}
+
+// RuleSet holds every compiled Rule for an Engine's configured patterns,
+// keyed by PatternConfig.Name, and lives as long as the Engine so Cooldown
+// tracks "last surfaced" across repeated Analyze calls rather than
+// resetting each run. AnalyzeStream does no pattern matching and never
+// consults it.
+type RuleSet struct {
+	rules map[string]*Rule
+}
+
+// NewRuleSet compiles a Rule for every pattern that sets Regexes, When,
+// Cooldown, or MinOccurrences; a pattern using none of those has no Rule
+// and detectPatterns leaves its matches untouched. A pattern whose
+// Regexes or When fails to compile is dropped with a warning, the same
+// convention newPatternMatchers uses for a bad regex/expr - use
+// config.Config.ValidatePatterns to catch this before the engine starts
+// instead.
+func NewRuleSet(patterns []config.PatternConfig) *RuleSet {
+	rs := &RuleSet{rules: make(map[string]*Rule)}
+
+	var env *cel.Env
+	for _, p := range patterns {
+		if len(p.Regexes) == 0 && p.When == "" && p.Cooldown == 0 && p.MinOccurrences == 0 {
+			continue
+		}
+
+		rule := &Rule{cfg: p}
+
+		ok := true
+		for _, pattern := range p.Regexes {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				fmt.Printf("⚠️  pattern %q: compile regex %q: %v\n", p.Name, pattern, err)
+				ok = false
+				break
+			}
+			rule.regexes = append(rule.regexes, re)
+		}
+		if !ok {
+			continue
+		}
+
+		if p.When != "" {
+			if env == nil {
+				var err error
+				env, err = config.CELEnv()
+				if err != nil {
+					fmt.Printf("⚠️  pattern %q: build CEL environment: %v\n", p.Name, err)
+					continue
+				}
+			}
+			ast, issues := env.Compile(p.When)
+			if issues != nil && issues.Err() != nil {
+				fmt.Printf("⚠️  pattern %q: compile when: %v\n", p.Name, issues.Err())
+				continue
+			}
+			program, err := env.Program(ast)
+			if err != nil {
+				fmt.Printf("⚠️  pattern %q: build when program: %v\n", p.Name, err)
+				continue
+			}
+			rule.program = program
+		}
+
+		rs.rules[p.Name] = rule
+	}
+
+	return rs
+}
+
+// apply narrows match's Events down to those also satisfying the Rule
+// registered for match.Name (a no-op if there isn't one), recomputes
+// Occurrences/FirstSeen/LastSeen from the narrowed set, and gates the
+// result through Cooldown/MinOccurrences. The second return value is
+// false if match should be dropped entirely.
+func (rs *RuleSet) apply(match PatternMatch) (PatternMatch, bool) {
+	rule, ok := rs.rules[match.Name]
+	if !ok {
+		return match, true
+	}
+
+	var kept []*storage.Event
+	var keptCaptures []map[string]string
+	var firstSeen, lastSeen time.Time
+	for i, event := range match.Events {
+		hit, err := rule.matches(event)
+		if err != nil || !hit {
+			continue
+		}
+		kept = append(kept, event)
+		if i < len(match.Captures) {
+			keptCaptures = append(keptCaptures, match.Captures[i])
+		}
+		if firstSeen.IsZero() || event.Timestamp.Before(firstSeen) {
+			firstSeen = event.Timestamp
+		}
+		if lastSeen.IsZero() || event.Timestamp.After(lastSeen) {
+			lastSeen = event.Timestamp
+		}
+	}
+	if len(kept) == 0 {
+		return PatternMatch{}, false
+	}
+
+	match.Events = kept
+	if len(keptCaptures) > 0 {
+		match.Captures = keptCaptures
+	}
+	match.Occurrences = len(kept)
+	match.FirstSeen = firstSeen
+	match.LastSeen = lastSeen
+
+	if !rule.gate(match.Occurrences, lastSeen) {
+		return PatternMatch{}, false
+	}
+	return match, true
+}
@@ -3,7 +3,7 @@ package storage
 
 import (
 	"database/sql"
-	"fmt"
+	"encoding/json"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -19,22 +19,63 @@ type Event struct {
 	Metadata  map[string]string
 }
 
+// SQLiteStore is safe for concurrent use: all writes are serialized through
+// a single writer connection and a batching queue (see writequeue.go), and
+// reads run against a separate read-only connection pool so collectors
+// fanning out InsertEvents calls never trip "database is locked".
 type SQLiteStore struct {
-	db *sql.DB
+	db     *sql.DB // writer: WAL mode, MaxOpenConns(1)
+	readDB *sql.DB // readers: opened ro, can have many connections
+
+	queue *writeQueue
+
+	ftsAvailable bool // whether events_fts (FTS5) was created; see fts.go
+}
+
+func init() {
+	RegisterDriver("sqlite", func(dsn string) (Store, error) {
+		return NewSQLiteStore(dsn)
+	})
 }
 
 func NewSQLiteStore(path string) (*SQLiteStore, error) {
-	db, err := sql.Open("sqlite3", path)
+	return NewSQLiteStoreWithOptions(path, DefaultWriteQueueOptions())
+}
+
+// NewSQLiteStoreWithOptions opens path with the write queue tuned by opts,
+// for callers that need to trade write latency against batch size.
+func NewSQLiteStoreWithOptions(path string, opts WriteQueueOptions) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", path+"?_journal_mode=WAL&_synchronous=NORMAL&_busy_timeout=5000")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1) // SQLite has one writer; more connections just serialize anyway
+
+	if _, err := db.Exec(`
+		PRAGMA journal_mode=WAL;
+		PRAGMA synchronous=NORMAL;
+		PRAGMA busy_timeout=5000;
+		PRAGMA temp_store=MEMORY;
+	`); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	readDB, err := sql.Open("sqlite3", "file:"+path+"?mode=ro&_journal_mode=WAL")
 	if err != nil {
+		db.Close()
 		return nil, err
 	}
 
-	store := &SQLiteStore{db: db}
+	store := &SQLiteStore{db: db, readDB: readDB}
 	if err := store.initialize(); err != nil {
 		db.Close()
+		readDB.Close()
 		return nil, err
 	}
 
+	store.queue = newWriteQueue(store, opts)
+
 	return store, nil
 }
 
@@ -74,22 +115,49 @@ func (s *SQLiteStore) initialize() error {
 		count INTEGER DEFAULT 1,
 		severity TEXT
 	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_patterns_name ON patterns(pattern_name);
+
+	CREATE TABLE IF NOT EXISTS checkpoints (
+		source TEXT NOT NULL,
+		key TEXT NOT NULL,
+		offset INTEGER NOT NULL DEFAULT 0,
+		inode INTEGER NOT NULL DEFAULT 0,
+		updated_at DATETIME NOT NULL,
+		PRIMARY KEY (source, key)
+	);
+
+	CREATE TABLE IF NOT EXISTS analyses (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		fingerprint TEXT NOT NULL,
+		timestamp DATETIME NOT NULL,
+		data TEXT NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_analyses_fingerprint ON analyses(fingerprint, timestamp);
 	`
 
-	_, err := s.db.Exec(schema)
-	return err
+	if _, err := s.db.Exec(schema); err != nil {
+		return err
+	}
+
+	return s.initializeFTS()
 }
 
+// InsertEvent enqueues event on the write queue and blocks until it has
+// been committed as part of some batch. Under load, many concurrent
+// InsertEvent/InsertEvents callers end up sharing a single transaction.
 func (s *SQLiteStore) InsertEvent(event *Event) error {
-	query := `
-	INSERT INTO events (timestamp, source, severity, message, event_id)
-	VALUES (?, ?, ?, ?, ?)
-	`
-	_, err := s.db.Exec(query, event.Timestamp, event.Source, event.Severity, event.Message, event.EventID)
-	return err
+	return s.queue.enqueue([]*Event{event})
 }
 
 func (s *SQLiteStore) InsertEvents(events []*Event) error {
+	return s.queue.enqueue(events)
+}
+
+// writeBatch is the only place that actually touches the writer connection;
+// it is called exclusively from the write queue's flusher goroutine.
+func (s *SQLiteStore) writeBatch(events []*Event) error {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return err
@@ -97,8 +165,8 @@ func (s *SQLiteStore) InsertEvents(events []*Event) error {
 	defer tx.Rollback()
 
 	stmt, err := tx.Prepare(`
-		INSERT INTO events (timestamp, source, severity, message, event_id)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO events (timestamp, source, severity, message, event_id, raw_data)
+		VALUES (?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return err
@@ -106,7 +174,11 @@ func (s *SQLiteStore) InsertEvents(events []*Event) error {
 	defer stmt.Close()
 
 	for _, event := range events {
-		_, err := stmt.Exec(event.Timestamp, event.Source, event.Severity, event.Message, event.EventID)
+		rawData, err := encodeMetadata(event.Metadata)
+		if err != nil {
+			return err
+		}
+		_, err = stmt.Exec(event.Timestamp, event.Source, event.Severity, event.Message, event.EventID, rawData)
 		if err != nil {
 			return err
 		}
@@ -115,9 +187,48 @@ func (s *SQLiteStore) InsertEvents(events []*Event) error {
 	return tx.Commit()
 }
 
+// encodeMetadata serializes an event's metadata into the raw_data column.
+// Empty/nil metadata stores as NULL rather than "{}" so existing rows
+// written before this field existed aren't distinguishable from ones with
+// no metadata.
+func encodeMetadata(metadata map[string]string) (interface{}, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// decodeMetadata is the inverse of encodeMetadata, tolerant of NULL/empty
+// raw_data from rows written before this column was populated.
+func decodeMetadata(rawData sql.NullString) map[string]string {
+	if !rawData.Valid || rawData.String == "" {
+		return nil
+	}
+	var metadata map[string]string
+	if err := json.Unmarshal([]byte(rawData.String), &metadata); err != nil {
+		return nil
+	}
+	return metadata
+}
+
+// Flush blocks until every event enqueued so far has been committed.
+func (s *SQLiteStore) Flush() error {
+	return s.queue.flush()
+}
+
+// WriteMetrics reports current write-queue health for operators tuning
+// WriteQueueOptions.
+func (s *SQLiteStore) WriteMetrics() WriteMetrics {
+	return s.queue.metrics()
+}
+
 func (s *SQLiteStore) GetEvents(start, end time.Time, source string) ([]*Event, error) {
 	query := `
-	SELECT id, timestamp, source, severity, message, event_id
+	SELECT id, timestamp, source, severity, message, event_id, raw_data
 	FROM events
 	WHERE timestamp BETWEEN ? AND ?
 	`
@@ -130,7 +241,7 @@ func (s *SQLiteStore) GetEvents(start, end time.Time, source string) ([]*Event,
 
 	query += " ORDER BY timestamp ASC"
 
-	rows, err := s.db.Query(query, args...)
+	rows, err := s.readDB.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -138,8 +249,7 @@ func (s *SQLiteStore) GetEvents(start, end time.Time, source string) ([]*Event,
 
 	var events []*Event
 	for rows.Next() {
-		event := &Event{}
-		err := rows.Scan(&event.ID, &event.Timestamp, &event.Source, &event.Severity, &event.Message, &event.EventID)
+		event, err := scanEvent(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -149,9 +259,65 @@ func (s *SQLiteStore) GetEvents(start, end time.Time, source string) ([]*Event,
 	return events, rows.Err()
 }
 
+// StreamEvents runs the same query as GetEvents but returns rows one at a
+// time through an EventIterator instead of buffering every match into a
+// slice, so a caller analyzing a multi-GB store doesn't have to hold it
+// all in memory.
+func (s *SQLiteStore) StreamEvents(start, end time.Time, source string) (EventIterator, error) {
+	query := `
+	SELECT id, timestamp, source, severity, message, event_id, raw_data
+	FROM events
+	WHERE timestamp BETWEEN ? AND ?
+	`
+	args := []interface{}{start, end}
+
+	if source != "" && source != "all" {
+		query += " AND source = ?"
+		args = append(args, source)
+	}
+
+	query += " ORDER BY timestamp ASC"
+
+	rows, err := s.readDB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlEventIterator{rows: rows}, nil
+}
+
+// sqlEventIterator adapts *sql.Rows from GetEvents/StreamEvents' shared
+// column layout to storage.EventIterator.
+type sqlEventIterator struct {
+	rows *sql.Rows
+}
+
+func (it *sqlEventIterator) Next() (*Event, error) {
+	if !it.rows.Next() {
+		return nil, it.rows.Err()
+	}
+	return scanEvent(it.rows)
+}
+
+func (it *sqlEventIterator) Close() error {
+	return it.rows.Close()
+}
+
+// scanEvent reads a row produced by any query that selects
+// (id, timestamp, source, severity, message, event_id, raw_data) in that
+// order, decoding raw_data back into Event.Metadata.
+func scanEvent(rows *sql.Rows) (*Event, error) {
+	event := &Event{}
+	var rawData sql.NullString
+	if err := rows.Scan(&event.ID, &event.Timestamp, &event.Source, &event.Severity, &event.Message, &event.EventID, &rawData); err != nil {
+		return nil, err
+	}
+	event.Metadata = decodeMetadata(rawData)
+	return event, nil
+}
+
 func (s *SQLiteStore) GetEventCount(start, end time.Time) (int64, error) {
 	var count int64
-	err := s.db.QueryRow(`
+	err := s.readDB.QueryRow(`
 		SELECT COUNT(*) FROM events
 		WHERE timestamp BETWEEN ? AND ?
 	`, start, end).Scan(&count)
@@ -159,7 +325,7 @@ func (s *SQLiteStore) GetEventCount(start, end time.Time) (int64, error) {
 }
 
 func (s *SQLiteStore) GetEventsBySeverity(start, end time.Time) (map[string]int64, error) {
-	rows, err := s.db.Query(`
+	rows, err := s.readDB.Query(`
 		SELECT severity, COUNT(*) as count
 		FROM events
 		WHERE timestamp BETWEEN ? AND ?
@@ -184,7 +350,7 @@ func (s *SQLiteStore) GetEventsBySeverity(start, end time.Time) (map[string]int6
 }
 
 func (s *SQLiteStore) GetEventsBySource(start, end time.Time) (map[string]int64, error) {
-	rows, err := s.db.Query(`
+	rows, err := s.readDB.Query(`
 		SELECT source, COUNT(*) as count
 		FROM events
 		WHERE timestamp BETWEEN ? AND ?
@@ -212,14 +378,14 @@ func (s *SQLiteStore) GetEventsBySource(start, end time.Time) (map[string]int64,
 func (s *SQLiteStore) GetErrorRate(start, end time.Time) (float64, error) {
 	var total, errors int64
 
-	err := s.db.QueryRow(`
+	err := s.readDB.QueryRow(`
 		SELECT COUNT(*) FROM events WHERE timestamp BETWEEN ? AND ?
 	`, start, end).Scan(&total)
 	if err != nil {
 		return 0, err
 	}
 
-	err = s.db.QueryRow(`
+	err = s.readDB.QueryRow(`
 		SELECT COUNT(*) FROM events 
 		WHERE timestamp BETWEEN ? AND ?
 		AND (severity = 'ERROR' OR severity = 'FATAL' OR severity = 'CRITICAL')
@@ -237,8 +403,8 @@ func (s *SQLiteStore) GetErrorRate(start, end time.Time) (float64, error) {
 
 func (s *SQLiteStore) GetTimeSeriesData(start, end time.Time, bucketSize time.Duration) ([]TimeSeriesPoint, error) {
 	bucketSeconds := int(bucketSize.Seconds())
-	
-	rows, err := s.db.Query(`
+
+	rows, err := s.readDB.Query(`
 		SELECT 
 			(strftime('%s', timestamp) / ? * ?) as bucket,
 			COUNT(*) as total,
@@ -277,17 +443,21 @@ type TimeSeriesPoint struct {
 	Errors    int64
 }
 
-func (s *SQLiteStore) SearchMessages(pattern string, start, end time.Time, limit int) ([]*Event, error) {
+// SearchByMetadata finds events whose raw_data JSON has key set to value,
+// using SQLite's json_extract so the lookup can use the events table
+// without a separate metadata table. Requires SQLite built with JSON1
+// (bundled in mattn/go-sqlite3 by default).
+func (s *SQLiteStore) SearchByMetadata(key, value string, start, end time.Time, limit int) ([]*Event, error) {
 	query := `
-	SELECT id, timestamp, source, severity, message, event_id
+	SELECT id, timestamp, source, severity, message, event_id, raw_data
 	FROM events
 	WHERE timestamp BETWEEN ? AND ?
-	AND message LIKE ?
+	AND json_extract(raw_data, ?) = ?
 	ORDER BY timestamp DESC
 	LIMIT ?
 	`
 
-	rows, err := s.db.Query(query, start, end, "%"+pattern+"%", limit)
+	rows, err := s.readDB.Query(query, start, end, "$."+key, value, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -295,8 +465,7 @@ func (s *SQLiteStore) SearchMessages(pattern string, start, end time.Time, limit
 
 	var events []*Event
 	for rows.Next() {
-		event := &Event{}
-		err := rows.Scan(&event.ID, &event.Timestamp, &event.Source, &event.Severity, &event.Message, &event.EventID)
+		event, err := scanEvent(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -314,17 +483,56 @@ func (s *SQLiteStore) InsertMetric(timestamp time.Time, name string, value float
 	return err
 }
 
+// GetMetricSeries averages name's samples into step-wide buckets over
+// [start, end], aligned the same way GetTimeSeriesData buckets events -
+// suitable for feeding straight into a graph.
+func (s *SQLiteStore) GetMetricSeries(name string, start, end time.Time, step time.Duration) ([]MetricPoint, error) {
+	stepSeconds := int(step.Seconds())
+
+	rows, err := s.readDB.Query(`
+		SELECT
+			(strftime('%s', timestamp) / ? * ?) as bucket,
+			AVG(value) as avg_value
+		FROM metrics
+		WHERE metric_name = ? AND timestamp BETWEEN ? AND ?
+		GROUP BY bucket
+		ORDER BY bucket
+	`, stepSeconds, stepSeconds, name, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []MetricPoint
+	for rows.Next() {
+		var bucketTime int64
+		var value float64
+		if err := rows.Scan(&bucketTime, &value); err != nil {
+			return nil, err
+		}
+		points = append(points, MetricPoint{Timestamp: time.Unix(bucketTime, 0), Value: value})
+	}
+
+	return points, rows.Err()
+}
+
+// MetricPoint is one step-aligned, averaged bucket from GetMetricSeries.
+type MetricPoint struct {
+	Timestamp time.Time
+	Value     float64
+}
+
 func (s *SQLiteStore) GetStats() (*Stats, error) {
 	stats := &Stats{}
 
 	// Total events
-	err := s.db.QueryRow("SELECT COUNT(*) FROM events").Scan(&stats.TotalEvents)
+	err := s.readDB.QueryRow("SELECT COUNT(*) FROM events").Scan(&stats.TotalEvents)
 	if err != nil {
 		return nil, err
 	}
 
 	// Time range
-	err = s.db.QueryRow(`
+	err = s.readDB.QueryRow(`
 		SELECT MIN(timestamp), MAX(timestamp) FROM events
 	`).Scan(&stats.StartTime, &stats.EndTime)
 	if err != nil && err != sql.ErrNoRows {
@@ -332,7 +540,7 @@ func (s *SQLiteStore) GetStats() (*Stats, error) {
 	}
 
 	// Sources
-	rows, err := s.db.Query("SELECT DISTINCT source FROM events")
+	rows, err := s.readDB.Query("SELECT DISTINCT source FROM events")
 	if err != nil {
 		return nil, err
 	}
@@ -357,10 +565,16 @@ type Stats struct {
 }
 
 func (s *SQLiteStore) Close() error {
+	s.queue.stop()
+
+	if err := s.readDB.Close(); err != nil {
+		s.db.Close()
+		return err
+	}
 	return s.db.Close()
 }
 
 func (s *SQLiteStore) Vacuum() error {
 	_, err := s.db.Exec("VACUUM")
 	return err
-}
\ No newline at end of file
+}
@@ -0,0 +1,190 @@
+// internal/analyzer/statistics.go
+package analyzer
+
+import (
+	"math"
+	"time"
+
+	"incident-analyzer/internal/storage"
+)
+
+// SeriesStats is the mean and standard deviation of a per-minute count
+// series - the unit every baseline in this package is expressed in, so a
+// live analysis window bucketed the same way can be compared directly.
+type SeriesStats struct {
+	Mean   float64
+	StdDev float64
+}
+
+// SeasonalKey buckets a per-minute bin by hour-of-day and day-of-week, so
+// a baseline can say "9am on a Monday normally looks like this" instead of
+// one flat average for the whole history.
+type SeasonalKey struct {
+	Hour    int
+	Weekday time.Weekday
+}
+
+func seasonalKey(t time.Time) SeasonalKey {
+	return SeasonalKey{Hour: t.Hour(), Weekday: t.Weekday()}
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// standardDeviation returns the population standard deviation of values.
+func standardDeviation(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	m := mean(values)
+	var sumSq float64
+	for _, v := range values {
+		d := v - m
+		sumSq += d * d
+	}
+	return math.Sqrt(sumSq / float64(len(values)))
+}
+
+// ewma computes the exponentially-weighted moving average of values with
+// smoothing factor alpha (0 < alpha <= 1; higher reacts faster to recent
+// points), seeded with the series' own first value.
+func ewma(values []float64, alpha float64) []float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	smoothed := make([]float64, len(values))
+	smoothed[0] = values[0]
+	for i := 1; i < len(values); i++ {
+		smoothed[i] = alpha*values[i] + (1-alpha)*smoothed[i-1]
+	}
+	return smoothed
+}
+
+// minuteBin is one minute-wide slice of a time window: its actual start
+// time plus however many matching events fell in it.
+type minuteBin struct {
+	start time.Time
+	count int
+}
+
+// bucketPerMinute lays [start, end) out as 1-minute bins and counts, for
+// each bin, how many events satisfy keep (all events if keep is nil).
+// Bins with no matching events still appear with count 0, so the
+// resulting series lines up positionally with actual clock time.
+func bucketPerMinute(events []*storage.Event, start, end time.Time, keep func(*storage.Event) bool) []minuteBin {
+	if !end.After(start) {
+		return nil
+	}
+
+	n := int(math.Ceil(end.Sub(start).Minutes()))
+	bins := make([]minuteBin, n)
+	for i := range bins {
+		bins[i].start = start.Add(time.Duration(i) * time.Minute)
+	}
+
+	for _, event := range events {
+		if keep != nil && !keep(event) {
+			continue
+		}
+		if event.Timestamp.Before(start) || !event.Timestamp.Before(end) {
+			continue
+		}
+		idx := int(event.Timestamp.Sub(start).Minutes())
+		if idx >= 0 && idx < n {
+			bins[idx].count++
+		}
+	}
+
+	return bins
+}
+
+// counts extracts just the counts from bins, the shape mean/standardDeviation expect.
+func counts(bins []minuteBin) []float64 {
+	values := make([]float64, len(bins))
+	for i, b := range bins {
+		values[i] = float64(b.count)
+	}
+	return values
+}
+
+func isError(event *storage.Event) bool {
+	switch event.Severity {
+	case "ERROR", "FATAL", "CRITICAL":
+		return true
+	default:
+		return false
+	}
+}
+
+// buildBaseline computes overall, per-source, per-severity, and seasonal
+// statistics for events observed in [start, end), all as per-minute
+// counts, plus the overall error rate (errorRate is passed in rather than
+// recomputed here since Store already exposes GetErrorRate directly).
+func buildBaseline(events []*storage.Event, start, end time.Time, errorRate float64) *Baseline {
+	overall := bucketPerMinute(events, start, end, nil)
+	overallCounts := counts(overall)
+
+	errorBins := bucketPerMinute(events, start, end, isError)
+	errorRates := make([]float64, len(overall))
+	for i := range overall {
+		if overall[i].count > 0 {
+			errorRates[i] = float64(errorBins[i].count) / float64(overall[i].count) * 100
+		}
+	}
+
+	bySource := make(map[string]SeriesStats)
+	for _, source := range distinctValues(events, func(e *storage.Event) string { return e.Source }) {
+		src := source
+		bins := counts(bucketPerMinute(events, start, end, func(e *storage.Event) bool { return e.Source == src }))
+		bySource[source] = SeriesStats{Mean: mean(bins), StdDev: standardDeviation(bins)}
+	}
+
+	bySeverity := make(map[string]SeriesStats)
+	for _, severity := range distinctValues(events, func(e *storage.Event) string { return e.Severity }) {
+		sev := severity
+		bins := counts(bucketPerMinute(events, start, end, func(e *storage.Event) bool { return e.Severity == sev }))
+		bySeverity[severity] = SeriesStats{Mean: mean(bins), StdDev: standardDeviation(bins)}
+	}
+
+	seasonal := make(map[SeasonalKey][]float64)
+	for _, bin := range overall {
+		key := seasonalKey(bin.start)
+		seasonal[key] = append(seasonal[key], float64(bin.count))
+	}
+	seasonalStats := make(map[SeasonalKey]SeriesStats)
+	for key, values := range seasonal {
+		seasonalStats[key] = SeriesStats{Mean: mean(values), StdDev: standardDeviation(values)}
+	}
+
+	return &Baseline{
+		AvgEventsPerMinute: mean(overallCounts),
+		StdDev:             standardDeviation(overallCounts),
+		AvgErrorRate:       errorRate,
+		ErrorRateStdDev:    standardDeviation(errorRates),
+		CommonPatterns:     make(map[string]int),
+		BySource:           bySource,
+		BySeverity:         bySeverity,
+		Seasonal:           seasonalStats,
+	}
+}
+
+func distinctValues(events []*storage.Event, key func(*storage.Event) string) []string {
+	seen := make(map[string]bool)
+	var values []string
+	for _, e := range events {
+		v := key(e)
+		if !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	return values
+}
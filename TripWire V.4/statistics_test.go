@@ -0,0 +1,206 @@
+// internal/analyzer/statistics_test.go
+package analyzer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"incident-analyzer/internal/storage"
+)
+
+func approxEqual(a, b, epsilon float64) bool {
+	return math.Abs(a-b) <= epsilon
+}
+
+func TestMean(t *testing.T) {
+	cases := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"empty", nil, 0},
+		{"single", []float64{5}, 5},
+		{"mixed", []float64{1, 2, 3, 4}, 2.5},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := mean(c.values); !approxEqual(got, c.want, 1e-9) {
+				t.Errorf("mean(%v) = %v, want %v", c.values, got, c.want)
+			}
+		})
+	}
+}
+
+func TestStandardDeviation(t *testing.T) {
+	cases := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"empty", nil, 0},
+		{"constant", []float64{3, 3, 3, 3}, 0},
+		{"known", []float64{2, 4, 4, 4, 5, 5, 7, 9}, 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := standardDeviation(c.values); !approxEqual(got, c.want, 1e-9) {
+				t.Errorf("standardDeviation(%v) = %v, want %v", c.values, got, c.want)
+			}
+		})
+	}
+}
+
+func TestEWMA(t *testing.T) {
+	if got := ewma(nil, 0.5); got != nil {
+		t.Errorf("ewma(nil, 0.5) = %v, want nil", got)
+	}
+
+	values := []float64{10, 10, 10, 10}
+	smoothed := ewma(values, 0.5)
+	for i, v := range smoothed {
+		if !approxEqual(v, 10, 1e-9) {
+			t.Errorf("ewma flat series [%d] = %v, want 10", i, v)
+		}
+	}
+
+	// A single spike should be pulled toward it but never reach it, and
+	// every point after the spike should relax back down monotonically.
+	spiky := []float64{5, 5, 5, 50, 5, 5, 5}
+	smoothedSpiky := ewma(spiky, 0.5)
+	if smoothedSpiky[3] <= smoothedSpiky[2] || smoothedSpiky[3] >= spiky[3] {
+		t.Errorf("ewma should react to the spike without fully reaching it, got %v", smoothedSpiky[3])
+	}
+	for i := 4; i < len(smoothedSpiky)-1; i++ {
+		if smoothedSpiky[i+1] > smoothedSpiky[i] {
+			t.Errorf("ewma should relax back down after the spike, got %v then %v at index %d", smoothedSpiky[i], smoothedSpiky[i+1], i)
+		}
+	}
+}
+
+// syntheticEvents builds one ERROR event per minute at each offset in
+// errorMinutes and one INFO event per minute at each offset in infoMinutes,
+// all from source "svc", starting at base.
+func syntheticEvents(base time.Time, infoMinutes, errorMinutes []int) []*storage.Event {
+	var events []*storage.Event
+	for _, m := range infoMinutes {
+		events = append(events, &storage.Event{
+			Timestamp: base.Add(time.Duration(m) * time.Minute),
+			Source:    "svc",
+			Severity:  "INFO",
+			Message:   "steady state",
+		})
+	}
+	for _, m := range errorMinutes {
+		events = append(events, &storage.Event{
+			Timestamp: base.Add(time.Duration(m) * time.Minute),
+			Source:    "svc",
+			Severity:  "ERROR",
+			Message:   "boom",
+		})
+	}
+	return events
+}
+
+func TestBuildBaselineZScore(t *testing.T) {
+	base := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC) // a Monday
+	end := base.Add(60 * time.Minute)
+
+	// A steady 1 INFO event/minute baseline, with ERROR events only at the
+	// very end so the overall mean/stddev are computed from the quiet
+	// majority of bins.
+	infoMinutes := make([]int, 60)
+	for i := range infoMinutes {
+		infoMinutes[i] = i
+	}
+	events := syntheticEvents(base, infoMinutes, []int{59, 59, 59, 59, 59, 59, 59, 59, 59, 59})
+
+	baseline := buildBaseline(events, base, end, 0)
+
+	if !approxEqual(baseline.AvgEventsPerMinute, mean(counts(bucketPerMinute(events, base, end, nil))), 1e-9) {
+		t.Fatalf("AvgEventsPerMinute does not match a direct mean of the bucketed series")
+	}
+	if baseline.StdDev <= 0 {
+		t.Fatalf("expected nonzero StdDev given the spike at minute 59, got %v", baseline.StdDev)
+	}
+
+	// The spike bin's z-score should clear a conventional 3-sigma
+	// threshold, and an ordinary bin's should not.
+	spikeCount := 11.0 // 1 INFO + 10 ERROR
+	spikeZ := (spikeCount - baseline.AvgEventsPerMinute) / baseline.StdDev
+	if spikeZ < 3 {
+		t.Errorf("expected the injected spike to read as >= 3 sigma, got z=%v", spikeZ)
+	}
+	quietZ := (1 - baseline.AvgEventsPerMinute) / baseline.StdDev
+	if math.Abs(quietZ) >= 3 {
+		t.Errorf("expected an ordinary 1-event bin to stay under 3 sigma, got z=%v", quietZ)
+	}
+
+	if src, ok := baseline.BySource["svc"]; !ok || src.Mean <= 0 {
+		t.Errorf("expected a populated BySource entry for svc, got %+v", src)
+	}
+	if sev, ok := baseline.BySeverity["ERROR"]; !ok || sev.Mean <= 0 {
+		t.Errorf("expected a populated BySeverity entry for ERROR, got %+v", sev)
+	}
+}
+
+func TestBuildBaselineSeasonal(t *testing.T) {
+	// Three consecutive Mondays, each with a busy 9am hour (10
+	// events/minute) against an otherwise-quiet day (1 event/minute), so a
+	// baseline built across all three should learn that 9am-on-a-Monday is
+	// normal rather than anomalous.
+	var events []*storage.Event
+	var weekStart time.Time
+	for week := 0; week < 3; week++ {
+		day := time.Date(2026, 1, 5+7*week, 0, 0, 0, 0, time.UTC)
+		if week == 0 {
+			weekStart = day
+		}
+		for minute := 0; minute < 24*60; minute++ {
+			ts := day.Add(time.Duration(minute) * time.Minute)
+			count := 1
+			if ts.Hour() == 9 {
+				count = 10
+			}
+			for i := 0; i < count; i++ {
+				events = append(events, &storage.Event{
+					Timestamp: ts,
+					Source:    "svc",
+					Severity:  "INFO",
+					Message:   "tick",
+				})
+			}
+		}
+	}
+
+	end := weekStart.Add(21 * 24 * time.Hour)
+	baseline := buildBaseline(events, weekStart, end, 0)
+
+	nineAM := SeasonalKey{Hour: 9, Weekday: time.Monday}
+	stats, ok := baseline.Seasonal[nineAM]
+	if !ok {
+		t.Fatalf("expected a seasonal entry for 9am Monday")
+	}
+	if !approxEqual(stats.Mean, 10, 1e-9) {
+		t.Errorf("expected 9am Monday's seasonal mean to reflect its own busy baseline (10), got %v", stats.Mean)
+	}
+
+	// A live 9am-Monday bin at the learned rate should not read as
+	// anomalous against its own seasonal bucket, even though it is far
+	// above the flat overall average.
+	liveCount := 10.0
+	seasonalZ := 0.0
+	if stats.StdDev > 0 {
+		seasonalZ = (liveCount - stats.Mean) / stats.StdDev
+	}
+	if math.Abs(seasonalZ) >= 3 {
+		t.Errorf("expected the normal 9am Monday rate to read as in-baseline seasonally, got z=%v", seasonalZ)
+	}
+	if liveCount <= baseline.AvgEventsPerMinute {
+		t.Fatalf("test setup invalid: 9am rate should exceed the flat overall average")
+	}
+	flatZ := (liveCount - baseline.AvgEventsPerMinute) / baseline.StdDev
+	if flatZ < 3 {
+		t.Errorf("expected the same rate to read as anomalous against the flat overall baseline, got z=%v", flatZ)
+	}
+}
@@ -0,0 +1,93 @@
+// internal/storage/store.go
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// Store is the persistence interface every backend (SQLite, Postgres, ...)
+// must satisfy. Collectors, the analyzer engine, and reporters all depend
+// on this interface rather than a concrete driver so the storage layer can
+// be swapped via config without touching the rest of the tree.
+type Store interface {
+	InsertEvent(event *Event) error
+	InsertEvents(events []*Event) error
+	GetEvents(start, end time.Time, source string) ([]*Event, error)
+	StreamEvents(start, end time.Time, source string) (EventIterator, error)
+	GetEventCount(start, end time.Time) (int64, error)
+	GetEventsBySeverity(start, end time.Time) (map[string]int64, error)
+	GetEventsBySource(start, end time.Time) (map[string]int64, error)
+	GetErrorRate(start, end time.Time) (float64, error)
+	GetTimeSeriesData(start, end time.Time, bucketSize time.Duration) ([]TimeSeriesPoint, error)
+	SearchMessages(query string, start, end time.Time, limit int) ([]SearchResult, error)
+	SearchByMetadata(key, value string, start, end time.Time, limit int) ([]*Event, error)
+	RebuildSearchIndex() error
+	GetCheckpoint(source, key string) (*Checkpoint, error)
+	SaveCheckpoint(cp *Checkpoint) error
+	UpsertPattern(name string, firstSeen, lastSeen time.Time, severity string, count int64) error
+	GetTopPatterns(start, end time.Time, limit int) ([]*Pattern, error)
+	ListPatternTemplates() ([]*Pattern, error)
+	GetPatternEvents(patternID int64, limit int) ([]*Event, error)
+	InsertMetric(timestamp time.Time, name string, value float64, source string) error
+	GetMetricSeries(name string, start, end time.Time, step time.Duration) ([]MetricPoint, error)
+	SaveAnalysis(fingerprint string, timestamp time.Time, data string) (int64, error)
+	ListAnalyses(start, end time.Time) ([]*Analysis, error)
+	GetAnalysisByFingerprint(fingerprint string, since time.Time) ([]*Analysis, error)
+	GetStats() (*Stats, error)
+	Close() error
+	Vacuum() error
+}
+
+// EventIterator yields events one at a time in ascending timestamp order,
+// for callers processing a range too large to hold in memory at once
+// (see analyzer.AnalyzeStream). Next returns (nil, nil) once exhausted.
+// Callers must call Close when done, whether or not Next ever errored.
+type EventIterator interface {
+	Next() (*Event, error)
+	Close() error
+}
+
+// SearchResult pairs a matched event with its relevance score from
+// SearchMessages - BM25 for SQLite (lower is more relevant), ts_rank for
+// Postgres (higher is more relevant). Compare scores only within a single
+// backend's results, never across backends.
+type SearchResult struct {
+	Event *Event
+	Rank  float64
+}
+
+// Pattern is a recurring log template mined by internal/patterns, one row
+// per distinct template in the patterns table.
+type Pattern struct {
+	ID        int64
+	Name      string
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Count     int64
+	Severity  string
+}
+
+// driverFunc opens a Store given a driver-specific DSN/path.
+type driverFunc func(dsn string) (Store, error)
+
+var drivers = make(map[string]driverFunc)
+
+// RegisterDriver makes a storage backend available under name (e.g.
+// "sqlite", "postgres"). Backend packages call this from an init() so that
+// Open can construct them without storage importing the backend package
+// directly - the same pattern database/sql uses for its drivers.
+func RegisterDriver(name string, fn func(dsn string) (Store, error)) {
+	drivers[name] = fn
+}
+
+// Open constructs the Store registered under driver, using dsn as its
+// connection string (a filesystem path for sqlite, a libpq connection
+// string for postgres). Callers typically pass cfg.Storage.Driver/DSN.
+func Open(driver, dsn string) (Store, error) {
+	fn, ok := drivers[driver]
+	if !ok {
+		return nil, fmt.Errorf("storage: unknown driver %q", driver)
+	}
+	return fn(dsn)
+}
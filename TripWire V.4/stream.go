@@ -0,0 +1,435 @@
+// internal/analyzer/stream.go
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"math/rand"
+	"sort"
+	"time"
+
+	"incident-analyzer/internal/config"
+)
+
+// PartialResult is a snapshot of an in-progress AnalyzeStream run. Every
+// field is a running total or an online-algorithm estimate computed from
+// events seen so far, not the whole range - a caller renders it as "so
+// far" progress and replaces it with the next snapshot, using the last
+// one (Done == true) as the final result.
+type PartialResult struct {
+	EventsProcessed int64
+	ElapsedTime     time.Duration
+
+	ErrorCount   int64
+	WarningCount int64
+	ErrorRate    float64
+
+	// AvgEventsPerMinute/EventsPerMinuteStdDev are computed online via
+	// Welford's algorithm over closed 1-minute bins, so they never require
+	// buffering per-minute counts for the whole range.
+	AvgEventsPerMinute    float64
+	EventsPerMinuteStdDev float64
+
+	// UniqueSources is a HyperLogLog cardinality estimate, not an exact
+	// count - accurate to within a few percent using O(1) memory
+	// regardless of how many distinct sources actually appear.
+	UniqueSources uint64
+
+	// TopErrors is approximate: frequencies come from a count-min sketch,
+	// so a reported Count may be (rarely) an overestimate.
+	TopErrors []ErrorSummary
+
+	// Timeline is a reservoir sample of error-level events, giving a
+	// representative spread across the whole range rather than just the
+	// first N seen.
+	Timeline []TimelineEvent
+
+	// Anomalies accumulates every per-minute rate anomaly found against
+	// baseline as each bin closes; nil if no baseline was supplied.
+	Anomalies []Anomaly
+
+	Done bool
+	// Err is set on the final PartialResult (Done == true) when the scan
+	// stopped because storage.EventIterator.Next returned an error rather
+	// than reaching genuine exhaustion - e.g. a dropped DB connection
+	// partway through a multi-GB store. Done alone can't tell these apart
+	// (EventIterator.Next returns (nil, nil) on exhaustion), so a caller
+	// must check Err before treating the final snapshot as complete.
+	Err error
+}
+
+// AnalyzeStream analyzes events in timeRange in a single pass over
+// storage.EventIterator, updating every aggregate online instead of
+// loading the whole range into memory like Analyze does. It returns
+// immediately with a channel that receives a PartialResult roughly every
+// streamEmitEvery events, and a final one with Done set once the range
+// is exhausted. The channel is unbuffered: a slow consumer backpressures
+// the scan, so a CLI/TUI can render each snapshot before the next
+// arrives.
+func (e *Engine) AnalyzeStream(ctx context.Context, timeRange config.TimeRange, baseline *Baseline) (<-chan PartialResult, error) {
+	iter, err := e.store.StreamEvents(timeRange.Start, timeRange.End, "all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to stream events: %w", err)
+	}
+
+	out := make(chan PartialResult)
+
+	go func() {
+		defer close(out)
+		defer iter.Close()
+
+		threshold := e.config.Thresholds.AnomalyStdDev
+		started := time.Now()
+
+		welford := &welfordAccumulator{}
+		sources := newHyperLogLog(14)
+		topErrors := newTopErrorTracker(4, 2048, 200)
+		timeline := newReservoirSampler(20)
+
+		var eventsProcessed, errorCount, warningCount int64
+		var anomalies []Anomaly
+		var binStart time.Time
+		var binCount int64
+
+		closeBin := func() {
+			welford.Add(float64(binCount))
+			if baseline != nil && baseline.StdDev > 0 {
+				z := (float64(binCount) - baseline.AvgEventsPerMinute) / baseline.StdDev
+				if math.Abs(z) <= threshold {
+					return
+				}
+
+				if seasonal, ok := baseline.Seasonal[seasonalKey(binStart)]; ok && seasonal.StdDev > 0 {
+					seasonalZ := (float64(binCount) - seasonal.Mean) / seasonal.StdDev
+					if math.Abs(seasonalZ) <= threshold {
+						return // expected for this hour/weekday, not an anomaly - see detectAnomalies
+					}
+				}
+
+				deviation := (float64(binCount) - baseline.AvgEventsPerMinute) / baseline.AvgEventsPerMinute * 100
+				anomalies = append(anomalies, Anomaly{
+					Type:        "Event Rate Spike",
+					Timestamp:   binStart,
+					Severity:    "HIGH",
+					Description: fmt.Sprintf("%d events/min at %s is %.1f%% above baseline (%.1f/min, z=%.1f)", binCount, binStart.Format("15:04"), deviation, baseline.AvgEventsPerMinute, z),
+					Value:       float64(binCount),
+					Baseline:    baseline.AvgEventsPerMinute,
+					Deviation:   deviation,
+				})
+			}
+		}
+
+		snapshot := func(done bool, scanErr error) PartialResult {
+			var errorRate float64
+			if eventsProcessed > 0 {
+				errorRate = float64(errorCount) / float64(eventsProcessed) * 100
+			}
+			return PartialResult{
+				EventsProcessed:       eventsProcessed,
+				ElapsedTime:           time.Since(started),
+				ErrorCount:            errorCount,
+				WarningCount:          warningCount,
+				ErrorRate:             errorRate,
+				AvgEventsPerMinute:    welford.Mean(),
+				EventsPerMinuteStdDev: welford.StdDev(),
+				UniqueSources:         sources.Estimate(),
+				TopErrors:             topErrors.Top(10),
+				Timeline:              timeline.Sample(),
+				Anomalies:             append([]Anomaly(nil), anomalies...),
+				Done:                  done,
+				Err:                   scanErr,
+			}
+		}
+
+		var scanErr error
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			event, err := iter.Next()
+			if err != nil {
+				scanErr = fmt.Errorf("stream events: %w", err)
+				break
+			}
+			if event == nil {
+				break
+			}
+
+			eventsProcessed++
+			sources.Add(event.Source)
+
+			minute := event.Timestamp.Truncate(time.Minute)
+			if binStart.IsZero() {
+				binStart = minute
+			}
+			for binStart.Before(minute) {
+				closeBin()
+				binCount = 0
+				binStart = binStart.Add(time.Minute)
+			}
+			binCount++
+
+			switch event.Severity {
+			case "ERROR", "FATAL", "CRITICAL":
+				errorCount++
+				topErrors.Observe(event.Message, event.Source)
+				timeline.Add(TimelineEvent{
+					Timestamp: event.Timestamp,
+					Source:    event.Source,
+					Severity:  event.Severity,
+					Message:   event.Message,
+					EventType: "error",
+				})
+			case "WARN", "WARNING":
+				warningCount++
+			}
+
+			if eventsProcessed%streamEmitEvery == 0 {
+				select {
+				case out <- snapshot(false, nil):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+
+		if eventsProcessed > 0 {
+			closeBin()
+		}
+
+		select {
+		case out <- snapshot(true, scanErr):
+		case <-ctx.Done():
+		}
+	}()
+
+	return out, nil
+}
+
+// streamEmitEvery is how many processed events pass between PartialResult
+// snapshots.
+const streamEmitEvery = 1000
+
+// welfordAccumulator computes a running mean and variance in one pass
+// using Welford's online algorithm, so neither needs the full sample held
+// in memory the way statistics.go's mean/standardDeviation do.
+type welfordAccumulator struct {
+	count int64
+	mean  float64
+	m2    float64
+}
+
+func (w *welfordAccumulator) Add(x float64) {
+	w.count++
+	delta := x - w.mean
+	w.mean += delta / float64(w.count)
+	w.m2 += delta * (x - w.mean)
+}
+
+func (w *welfordAccumulator) Mean() float64 {
+	return w.mean
+}
+
+func (w *welfordAccumulator) StdDev() float64 {
+	if w.count == 0 {
+		return 0
+	}
+	return math.Sqrt(w.m2 / float64(w.count))
+}
+
+// countMinSketch estimates per-key frequencies in a fixed-size table
+// instead of a map keyed by every distinct value seen, trading a small,
+// always-an-overestimate error for O(depth*width) memory regardless of
+// key cardinality.
+type countMinSketch struct {
+	depth, width int
+	table        [][]uint64
+	seeds        []uint32
+}
+
+func newCountMinSketch(depth, width int) *countMinSketch {
+	table := make([][]uint64, depth)
+	seeds := make([]uint32, depth)
+	for i := range table {
+		table[i] = make([]uint64, width)
+		seeds[i] = uint32(i)*2654435761 + 1
+	}
+	return &countMinSketch{depth: depth, width: width, table: table, seeds: seeds}
+}
+
+func (c *countMinSketch) index(row int, key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int((h.Sum32() ^ c.seeds[row]) % uint32(c.width))
+}
+
+func (c *countMinSketch) Add(key string) {
+	for row := 0; row < c.depth; row++ {
+		c.table[row][c.index(row, key)]++
+	}
+}
+
+func (c *countMinSketch) Estimate(key string) uint64 {
+	min := uint64(math.MaxUint64)
+	for row := 0; row < c.depth; row++ {
+		if v := c.table[row][c.index(row, key)]; v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+// topErrorTracker reports the heaviest-hitting error messages without
+// storing an entry per distinct message: frequency comes from a
+// countMinSketch, while a small bounded set of "candidate" messages
+// (evicting whichever has the lowest sketch estimate when full) tracks
+// which keys are actually worth ranking.
+type topErrorTracker struct {
+	sketch        *countMinSketch
+	maxCandidates int
+	candidates    map[string]bool
+	sources       map[string]map[string]bool
+}
+
+func newTopErrorTracker(depth, width, maxCandidates int) *topErrorTracker {
+	return &topErrorTracker{
+		sketch:        newCountMinSketch(depth, width),
+		maxCandidates: maxCandidates,
+		candidates:    make(map[string]bool),
+		sources:       make(map[string]map[string]bool),
+	}
+}
+
+func (t *topErrorTracker) Observe(message, source string) {
+	t.sketch.Add(message)
+
+	if !t.candidates[message] {
+		if len(t.candidates) >= t.maxCandidates {
+			t.evictLowest()
+		}
+		t.candidates[message] = true
+		t.sources[message] = make(map[string]bool)
+	}
+	t.sources[message][source] = true
+}
+
+func (t *topErrorTracker) evictLowest() {
+	var worst string
+	worstCount := uint64(math.MaxUint64)
+	for message := range t.candidates {
+		if c := t.sketch.Estimate(message); c < worstCount {
+			worst, worstCount = message, c
+		}
+	}
+	if worst != "" {
+		delete(t.candidates, worst)
+		delete(t.sources, worst)
+	}
+}
+
+func (t *topErrorTracker) Top(n int) []ErrorSummary {
+	type scored struct {
+		message string
+		count   uint64
+	}
+	scoredList := make([]scored, 0, len(t.candidates))
+	for message := range t.candidates {
+		scoredList = append(scoredList, scored{message, t.sketch.Estimate(message)})
+	}
+	sort.Slice(scoredList, func(i, j int) bool { return scoredList[i].count > scoredList[j].count })
+	if len(scoredList) > n {
+		scoredList = scoredList[:n]
+	}
+
+	summaries := make([]ErrorSummary, len(scoredList))
+	for i, s := range scoredList {
+		var srcs []string
+		for source := range t.sources[s.message] {
+			srcs = append(srcs, source)
+		}
+		sort.Strings(srcs)
+		summaries[i] = ErrorSummary{Message: s.message, Count: int(s.count), Sources: srcs}
+	}
+	return summaries
+}
+
+// hyperLogLog estimates the number of distinct items added using O(2^p)
+// memory regardless of how many items (or duplicates) are actually added.
+type hyperLogLog struct {
+	p         uint
+	m         uint32
+	registers []uint8
+}
+
+func newHyperLogLog(p uint) *hyperLogLog {
+	m := uint32(1) << p
+	return &hyperLogLog{p: p, m: m, registers: make([]uint8, m)}
+}
+
+func (h *hyperLogLog) Add(item string) {
+	hasher := fnv.New64a()
+	hasher.Write([]byte(item))
+	x := hasher.Sum64()
+
+	idx := x >> (64 - h.p)
+	rest := (x << h.p) | (1<<h.p - 1)
+	rho := uint8(bits.LeadingZeros64(rest)) + 1
+	if rho > h.registers[idx] {
+		h.registers[idx] = rho
+	}
+}
+
+func (h *hyperLogLog) Estimate() uint64 {
+	sumInv := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sumInv += 1 / math.Pow(2, float64(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	alpha := 0.7213 / (1 + 1.079/float64(h.m))
+	estimate := alpha * float64(h.m) * float64(h.m) / sumInv
+
+	if estimate <= 2.5*float64(h.m) && zeros > 0 {
+		estimate = float64(h.m) * math.Log(float64(h.m)/float64(zeros))
+	}
+	return uint64(estimate)
+}
+
+// reservoirSampler keeps a uniform random sample of size items out of an
+// arbitrarily long stream, seen one at a time, using Algorithm R.
+type reservoirSampler struct {
+	size  int
+	seen  int64
+	items []TimelineEvent
+	rng   *rand.Rand
+}
+
+func newReservoirSampler(size int) *reservoirSampler {
+	return &reservoirSampler{size: size, rng: rand.New(rand.NewSource(1))}
+}
+
+func (r *reservoirSampler) Add(item TimelineEvent) {
+	r.seen++
+	if len(r.items) < r.size {
+		r.items = append(r.items, item)
+		return
+	}
+	if j := r.rng.Int63n(r.seen); j < int64(r.size) {
+		r.items[j] = item
+	}
+}
+
+func (r *reservoirSampler) Sample() []TimelineEvent {
+	out := make([]TimelineEvent, len(r.items))
+	copy(out, r.items)
+	return out
+}
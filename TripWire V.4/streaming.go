@@ -0,0 +1,413 @@
+// internal/collectors/streaming.go
+package collectors
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"incident-analyzer/internal/config"
+	"incident-analyzer/internal/storage"
+)
+
+// StreamingCollector is the tailing counterpart to Collector: instead of
+// returning a bounded batch for a time range, it runs until ctx is
+// cancelled, pushing events onto out as they appear. Implementations
+// persist their read position via storage.Checkpoint so a restart resumes
+// instead of re-reading or skipping data.
+type StreamingCollector interface {
+	Name() string
+	Start(ctx context.Context, out chan<- *storage.Event) error
+}
+
+// Run starts every enabled streaming collector and multiplexes whatever
+// they push onto out into batched writes, flushing on size or a timer so
+// a single quiet source doesn't delay everything else. FileTailer writes
+// its own batches directly instead of using out (see tailOnce), since its
+// checkpoint has to wait on a confirmed write; out today only carries
+// WindowsTailer's events. Run blocks until ctx is cancelled, a batched
+// write fails, or every streaming collector has returned; an error from a
+// collector's Start is printed, not returned.
+func (m *Manager) Run(ctx context.Context) error {
+	var streamers []StreamingCollector
+	if m.config.Collectors["windows_events"].Enabled {
+		streamers = append(streamers, NewWindowsTailer(m.config, m.store))
+	}
+	if m.config.Collectors["application"].Enabled {
+		streamers = append(streamers, NewFileTailer(m.config, m.store))
+	}
+
+	out := make(chan *storage.Event, 256)
+
+	var wg sync.WaitGroup
+	for _, c := range streamers {
+		wg.Add(1)
+		go func(c StreamingCollector) {
+			defer wg.Done()
+			if err := c.Start(ctx, out); err != nil && ctx.Err() == nil {
+				fmt.Printf("  ⚠️  %s: %v\n", c.Name(), err)
+			}
+		}(c)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return m.batchWrite(ctx, out)
+}
+
+// batchWrite drains out into m.store in batches, flushing at streamWriteBatchSize
+// events or every streamFlushInterval, whichever comes first.
+func (m *Manager) batchWrite(ctx context.Context, out <-chan *storage.Event) error {
+	const streamWriteBatchSize = 100
+	const streamFlushInterval = 2 * time.Second
+
+	batch := make([]*storage.Event, 0, streamWriteBatchSize)
+	ticker := time.NewTicker(streamFlushInterval)
+	defer ticker.Stop()
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		start := time.Now()
+		err := m.store.InsertEvents(batch)
+		if m.metrics != nil {
+			m.metrics.ObserveWriteLatency(time.Since(start))
+		}
+		if err != nil {
+			if m.metrics != nil {
+				// batchWrite multiplexes every streaming collector, so
+				// there's no single source to attribute the error to.
+				m.metrics.ObserveCollectorError("stream")
+			}
+			batch = batch[:0]
+			return err
+		}
+		if m.metrics != nil {
+			bySeverity := make(map[string]int64)
+			for _, event := range batch {
+				bySeverity[event.Severity]++
+			}
+			for severity, n := range bySeverity {
+				m.metrics.ObserveEventsCollected("stream", severity, n)
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		select {
+		case event, ok := <-out:
+			if !ok {
+				return flush()
+			}
+			batch = append(batch, event)
+			if len(batch) >= streamWriteBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		case <-ticker.C:
+			if err := flush(); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			flush()
+			return ctx.Err()
+		}
+	}
+}
+
+// FileTailer is the streaming counterpart to FileCollector: it polls each
+// matched log file for new lines, reopening it on every poll and persisting a
+// (inode, byte offset) checkpoint per file so restarts resume instead of
+// re-scanning the whole file.
+type FileTailer struct {
+	config *config.Config
+	store  storage.Store
+}
+
+func NewFileTailer(cfg *config.Config, store storage.Store) *FileTailer {
+	return &FileTailer{config: cfg, store: store}
+}
+
+func (t *FileTailer) Name() string {
+	return "Application Logs (stream)"
+}
+
+func (t *FileTailer) Start(ctx context.Context, out chan<- *storage.Event) error {
+	var wg sync.WaitGroup
+	for _, basePath := range t.config.Collectors["application"].Paths {
+		wg.Add(1)
+		go func(basePath string) {
+			defer wg.Done()
+			t.tailPath(ctx, basePath, out)
+		}(basePath)
+	}
+	wg.Wait()
+	return nil
+}
+
+// tailPath walks basePath every 10 seconds to find matching files and
+// tails each newly found one in its own goroutine, so new files created
+// after Start runs are picked up on the next walk.
+func (t *FileTailer) tailPath(ctx context.Context, basePath string, out chan<- *storage.Event) {
+	tailed := make(map[string]bool)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		filepath.Walk(basePath, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info == nil || info.IsDir() {
+				return nil
+			}
+			if !strings.HasSuffix(path, ".log") && !strings.HasSuffix(path, ".txt") {
+				return nil
+			}
+			if tailed[path] {
+				return nil
+			}
+			tailed[path] = true
+			go t.tailFile(ctx, path, out)
+			return nil
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(10 * time.Second):
+		}
+	}
+}
+
+func (t *FileTailer) tailFile(ctx context.Context, path string, out chan<- *storage.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if err := t.tailOnce(path, out); err != nil {
+			fmt.Printf("    ⚠️  %s: %v\n", path, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// tailOnce reads whatever is new in path since the last checkpoint,
+// confirms it durably written, and only then saves an updated checkpoint.
+// It detects rotation by comparing inode: if the file now at path has a
+// different inode than the checkpoint, the old offset belongs to a file
+// that no longer exists at that path, so reading starts over from 0.
+//
+// The events read here are written via t.store.InsertEvents directly
+// rather than handed to out for Manager.batchWrite to pick up later:
+// batchWrite flushes on its own size/time cadence and drops a flush's
+// error on the floor, so a checkpoint saved as soon as lines reach that
+// channel could advance past events that are never actually persisted -
+// e.g. on a restart between the checkpoint write and the deferred flush,
+// or whenever InsertEvents itself fails. Writing here blocks until
+// SQLiteStore's write queue (see writequeue.go) has actually committed the
+// batch, so the checkpoint only ever points past events on disk.
+func (t *FileTailer) tailOnce(path string, out chan<- *storage.Event) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	inode := fileInode(info)
+
+	cp, err := t.store.GetCheckpoint("file", path)
+	if err != nil {
+		return err
+	}
+
+	var offset int64
+	if cp != nil && (cp.Inode == 0 || cp.Inode == inode) && cp.Offset <= info.Size() {
+		offset = cp.Offset
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	var events []*storage.Event
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			offset += int64(len(line))
+			if event := parseLogLine(strings.TrimRight(line, "\r\n"), filepath.Base(path)); event != nil {
+				events = append(events, event)
+			}
+		}
+		if err != nil {
+			break // io.EOF (or a read error) just means "nothing new yet"
+		}
+	}
+
+	if len(events) > 0 {
+		if err := t.store.InsertEvents(events); err != nil {
+			return fmt.Errorf("insert events: %w", err)
+		}
+	}
+
+	return t.store.SaveCheckpoint(&storage.Checkpoint{
+		Source:    "file",
+		Key:       path,
+		Offset:    offset,
+		Inode:     inode,
+		UpdatedAt: time.Now(),
+	})
+}
+
+func fileInode(info os.FileInfo) int64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int64(stat.Ino)
+	}
+	return 0
+}
+
+// parseLogLine applies the same timestamp/severity heuristics as
+// FileCollector.parseLogFile to a single line, with no time-range filter -
+// a tailer only ever sees new lines.
+func parseLogLine(line, source string) *storage.Event {
+	tsMatch := timestampRegex.FindString(line)
+	if tsMatch == "" {
+		return nil
+	}
+
+	ts, err := time.Parse("2006-01-02 15:04:05", strings.Replace(tsMatch, "T", " ", 1))
+	if err != nil {
+		return nil
+	}
+
+	severity := "INFO"
+	if match := severityRegex.FindString(line); match != "" {
+		severity = strings.ToUpper(match)
+	}
+
+	return &storage.Event{
+		Timestamp: ts,
+		Source:    source,
+		Severity:  severity,
+		Message:   line,
+	}
+}
+
+// WindowsTailer is the streaming counterpart to WindowsCollector. Since
+// Get-WinEvent has no native "follow" mode, it polls with -MaxEvents and
+// resumes from an EventBookmark persisted to a file between polls, rather
+// than re-filtering by StartTime/EndTime on every call.
+type WindowsTailer struct {
+	config *config.Config
+	store  storage.Store
+}
+
+func NewWindowsTailer(cfg *config.Config, store storage.Store) *WindowsTailer {
+	return &WindowsTailer{config: cfg, store: store}
+}
+
+func (w *WindowsTailer) Name() string {
+	return "Windows Events (stream)"
+}
+
+func (w *WindowsTailer) Start(ctx context.Context, out chan<- *storage.Event) error {
+	var wg sync.WaitGroup
+	for _, logName := range w.config.Collectors["windows_events"].Paths {
+		wg.Add(1)
+		go func(logName string) {
+			defer wg.Done()
+			w.tailLog(ctx, logName, out)
+		}(logName)
+	}
+	wg.Wait()
+	return nil
+}
+
+func (w *WindowsTailer) tailLog(ctx context.Context, logName string, out chan<- *storage.Event) {
+	bookmarkPath := filepath.Join(os.TempDir(), "incident-analyzer-"+logName+".bookmark")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		events, err := w.pollLog(ctx, logName, bookmarkPath)
+		if err != nil {
+			fmt.Printf("    ⚠️  %s: %v\n", logName, err)
+		}
+		for _, event := range events {
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// pollLog asks for events newer than the saved bookmark (or the oldest
+// ones in the log on first run, establishing a starting point to advance
+// forward from), then overwrites the bookmark file with the position of
+// the newest event returned so the next poll doesn't re-deliver it.
+// -Oldest is required on both calls: Get-WinEvent otherwise reads
+// newest-first, which combined with -Bookmark continues further backward
+// into the log instead of forward toward new events.
+func (w *WindowsTailer) pollLog(ctx context.Context, logName, bookmarkPath string) ([]*storage.Event, error) {
+	script := fmt.Sprintf(`
+		$bookmarkPath = '%s'
+		if (Test-Path $bookmarkPath) {
+			[xml]$bookmarkXml = Get-Content -Raw $bookmarkPath
+			$bookmark = New-Object System.Diagnostics.Eventing.Reader.EventBookmark($bookmarkXml.OuterXml)
+			$events = Get-WinEvent -LogName '%s' -Bookmark $bookmark -Oldest -MaxEvents 200 -ErrorAction SilentlyContinue
+		} else {
+			$events = Get-WinEvent -LogName '%s' -Oldest -MaxEvents 200 -ErrorAction SilentlyContinue
+		}
+		if ($events) {
+			$events | Select-Object TimeCreated,Id,LevelDisplayName,ProviderName,Message | ConvertTo-Json -Compress
+			$events[$events.Count - 1].Bookmark.ToXml() | Out-File -Encoding utf8 $bookmarkPath
+		}
+	`, bookmarkPath, logName, logName)
+
+	cmd := exec.CommandContext(ctx, "powershell", "-NoProfile", "-Command", script)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("powershell failed: %w", err)
+	}
+
+	return parseWindowsEvents(output, logName)
+}
@@ -0,0 +1,199 @@
+// internal/config/validate.go
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError is one problem found while validating a Config loaded by
+// LoadConfigStrict - a bad field value, not a YAML syntax error (those
+// come back as the underlying decode error instead).
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("config: %s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors collects every problem LoadConfigStrict's validation
+// pass found, so a caller sees everything wrong with a config at once
+// instead of fixing one field and re-running to discover the next.
+type ValidationErrors []*ValidationError
+
+func (errs ValidationErrors) Error() string {
+	if len(errs) == 1 {
+		return errs[0].Error()
+	}
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return fmt.Sprintf("config: %d validation errors:\n  %s", len(errs), strings.Join(msgs, "\n  "))
+}
+
+// validSeverities are the only values PatternConfig.Severity may take.
+var validSeverities = map[string]bool{
+	"CRITICAL": true,
+	"HIGH":     true,
+	"MEDIUM":   true,
+	"LOW":      true,
+	"INFO":     true,
+}
+
+// LoadConfigStrict parses the YAML file at path into a Config, unlike
+// LoadConfig which silently falls back to defaultConfig() on any error.
+// Unknown keys are rejected (KnownFields), environment overrides (see
+// applyEnvOverrides) are applied, and the result is validated before
+// being returned - an empty pattern name, an out-of-range severity, an
+// unparseable regex or When expression, a non-positive threshold, or an
+// enabled collector whose path doesn't exist all come back as a
+// ValidationErrors rather than a usable Config.
+func LoadConfigStrict(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: read %s: %w", path, err)
+	}
+
+	cfg := defaultConfig()
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("config: parse %s: %w", path, err)
+	}
+
+	applyEnvOverrides(cfg)
+
+	if errs := validate(cfg); len(errs) > 0 {
+		return nil, errs
+	}
+	return cfg, nil
+}
+
+func validate(cfg *Config) ValidationErrors {
+	var errs ValidationErrors
+
+	for i, p := range cfg.Patterns {
+		field := fmt.Sprintf("patterns[%d]", i)
+		if strings.TrimSpace(p.Name) == "" {
+			errs = append(errs, &ValidationError{field + ".name", "must not be empty"})
+		}
+		if p.Severity != "" && !validSeverities[p.Severity] {
+			errs = append(errs, &ValidationError{
+				field + ".severity",
+				fmt.Sprintf("must be one of CRITICAL, HIGH, MEDIUM, LOW, INFO, got %q", p.Severity),
+			})
+		}
+	}
+
+	if err := cfg.ValidatePatterns(); err != nil {
+		if patErrs, ok := err.(ValidationErrors); ok {
+			errs = append(errs, patErrs...)
+		} else {
+			errs = append(errs, &ValidationError{"patterns", err.Error()})
+		}
+	}
+
+	if cfg.Thresholds.ErrorRateMultiplier <= 0 {
+		errs = append(errs, &ValidationError{"thresholds.error_rate_multiplier", "must be positive"})
+	}
+	if cfg.Thresholds.AnomalyStdDev <= 0 {
+		errs = append(errs, &ValidationError{"thresholds.anomaly_std_dev", "must be positive"})
+	}
+	if cfg.Thresholds.MinConfidence <= 0 {
+		errs = append(errs, &ValidationError{"thresholds.min_confidence", "must be positive"})
+	}
+	if cfg.Thresholds.EWMAAlpha <= 0 {
+		errs = append(errs, &ValidationError{"thresholds.ewma_alpha", "must be positive"})
+	}
+
+	for name, c := range cfg.Collectors {
+		if !c.Enabled {
+			continue
+		}
+		for _, p := range c.Paths {
+			if !pathReachable(p) {
+				errs = append(errs, &ValidationError{
+					fmt.Sprintf("collectors.%s.paths", name),
+					fmt.Sprintf("%s: not found", p),
+				})
+			}
+		}
+	}
+
+	return errs
+}
+
+// ValidatePatterns pre-compiles every pattern's Regexes and When
+// expression - the same compilation analyzer.NewRuleSet does when an
+// Engine is constructed - and returns every failure found as a
+// ValidationErrors, so a bad rule is caught at config-load time rather
+// than silently dropped (with only a printed warning) when the engine
+// starts.
+func (cfg *Config) ValidatePatterns() error {
+	var errs ValidationErrors
+
+	var env *cel.Env
+	for i, p := range cfg.Patterns {
+		field := fmt.Sprintf("patterns[%d]", i)
+
+		for _, pattern := range p.Regexes {
+			if _, err := regexp.Compile(pattern); err != nil {
+				errs = append(errs, &ValidationError{field + ".regexes", fmt.Sprintf("%q: %v", pattern, err)})
+			}
+		}
+
+		if p.When == "" {
+			continue
+		}
+		if env == nil {
+			var err error
+			env, err = CELEnv()
+			if err != nil {
+				return fmt.Errorf("config: build CEL environment: %w", err)
+			}
+		}
+		if _, issues := env.Compile(p.When); issues != nil && issues.Err() != nil {
+			errs = append(errs, &ValidationError{field + ".when", issues.Err().Error()})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// CELEnv declares the variables a PatternConfig.When expression may
+// reference - kept exported so both ValidatePatterns and
+// analyzer.NewRuleSet compile against the same environment.
+func CELEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("source", cel.StringType),
+		cel.Variable("severity", cel.StringType),
+		cel.Variable("timestamp", cel.TimestampType),
+		cel.Variable("fields", cel.MapType(cel.StringType, cel.StringType)),
+	)
+}
+
+// pathReachable reports whether p exists on disk. Collector paths may be
+// glob patterns (the sql_server default uses "MSSQL*"), so a path
+// containing glob metacharacters is reachable if it expands to at least
+// one match rather than existing literally.
+func pathReachable(p string) bool {
+	if strings.ContainsAny(p, "*?[") {
+		matches, err := filepath.Glob(p)
+		return err == nil && len(matches) > 0
+	}
+	_, err := os.Stat(p)
+	return err == nil
+}
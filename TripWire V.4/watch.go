@@ -0,0 +1,83 @@
+// internal/config/watch.go
+package config
+
+import (
+	"context"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watch re-parses path via LoadConfigStrict and pushes the result on the
+// returned channel every time the file changes, until ctx is canceled, so
+// a long-running analyzer can pick up new patterns or thresholds without a
+// restart. Watch does not parse path when called, so nothing is sent
+// before the first change. A reload that fails to parse or validate
+// (for instance a half-written file mid-save) is skipped rather than sent
+// - the last good config stays in effect until the next valid write. The
+// channel is unbuffered, so a slow consumer backpressures delivery of the
+// next config, and it is closed once the watcher stops, whether from ctx
+// being canceled or from a setup failure.
+func Watch(ctx context.Context, path string) <-chan *Config {
+	out := make(chan *Config)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		close(out)
+		return out
+	}
+	if err := watcher.Add(path); err != nil {
+		watcher.Close()
+		close(out)
+		return out
+	}
+
+	go func() {
+		defer watcher.Close()
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+					// fsnotify watches the inode, not the path: editors and
+					// atomic saves (vim, VSCode, `mv`) commonly replace path
+					// by renaming a temp file over it, which surfaces here
+					// as the old inode being removed or renamed away rather
+					// than a Write. Without re-adding, the watch would go
+					// silently dead after the first such save. Add can fail
+					// if path is genuinely gone (not just mid-rename); skip
+					// this event rather than treat that as fatal.
+					if err := watcher.Add(path); err != nil {
+						continue
+					}
+				} else if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+
+				cfg, err := LoadConfigStrict(path)
+				if err != nil {
+					continue
+				}
+				select {
+				case out <- cfg:
+				case <-ctx.Done():
+					return
+				}
+
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
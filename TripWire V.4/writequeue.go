@@ -0,0 +1,184 @@
+// internal/storage/writequeue.go
+package storage
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WriteQueueOptions tunes how aggressively writes are batched. A batch is
+// flushed as soon as either bound is hit, whichever comes first.
+type WriteQueueOptions struct {
+	MaxBatchSize int           // flush once this many events are queued
+	MaxDelay     time.Duration // flush at most this long after the first event in a batch arrives
+	QueueDepth   int           // bounded channel size; InsertEvent(s) blocks once full
+}
+
+// DefaultWriteQueueOptions favors freshness over raw throughput: batches
+// flush every 100 events or 50ms, whichever comes first.
+func DefaultWriteQueueOptions() WriteQueueOptions {
+	return WriteQueueOptions{
+		MaxBatchSize: 100,
+		MaxDelay:     50 * time.Millisecond,
+		QueueDepth:   1000,
+	}
+}
+
+// WriteMetrics is a point-in-time snapshot of write-queue health, suitable
+// for logging or exposing on a /metrics endpoint.
+type WriteMetrics struct {
+	QueueDepth        int64
+	BatchesFlushed    int64
+	EventsWritten     int64
+	LastBatchSize     int64
+	TotalWriteLatency time.Duration
+}
+
+type writeRequest struct {
+	events []*Event
+	done   chan error
+}
+
+// writeQueue serializes InsertEvent/InsertEvents calls into batched
+// transactions on a single background flusher goroutine, so concurrent
+// collectors never contend for SQLite's single writer directly.
+type writeQueue struct {
+	store *SQLiteStore
+	opts  WriteQueueOptions
+
+	requests chan writeRequest
+	flushReq chan chan error
+	done     chan struct{}
+	wg       sync.WaitGroup
+
+	queueDepth     int64
+	batchesFlushed int64
+	eventsWritten  int64
+	lastBatchSize  int64
+	totalLatencyNs int64
+}
+
+func newWriteQueue(store *SQLiteStore, opts WriteQueueOptions) *writeQueue {
+	q := &writeQueue{
+		store:    store,
+		opts:     opts,
+		requests: make(chan writeRequest, opts.QueueDepth),
+		flushReq: make(chan chan error),
+		done:     make(chan struct{}),
+	}
+	q.wg.Add(1)
+	go q.run()
+	return q
+}
+
+func (q *writeQueue) enqueue(events []*Event) error {
+	req := writeRequest{events: events, done: make(chan error, 1)}
+	atomic.AddInt64(&q.queueDepth, 1)
+	q.requests <- req
+	return <-req.done
+}
+
+// flush blocks until every request queued before this call returns.
+func (q *writeQueue) flush() error {
+	ack := make(chan error, 1)
+	q.flushReq <- ack
+	return <-ack
+}
+
+func (q *writeQueue) stop() {
+	q.flush()
+	close(q.done)
+	q.wg.Wait()
+}
+
+func (q *writeQueue) metrics() WriteMetrics {
+	return WriteMetrics{
+		QueueDepth:        atomic.LoadInt64(&q.queueDepth),
+		BatchesFlushed:    atomic.LoadInt64(&q.batchesFlushed),
+		EventsWritten:     atomic.LoadInt64(&q.eventsWritten),
+		LastBatchSize:     atomic.LoadInt64(&q.lastBatchSize),
+		TotalWriteLatency: time.Duration(atomic.LoadInt64(&q.totalLatencyNs)),
+	}
+}
+
+func (q *writeQueue) run() {
+	defer q.wg.Done()
+
+	var pending []writeRequest
+	var pendingEvents int
+	var timer *time.Timer
+
+	resetTimer := func() {
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.NewTimer(q.opts.MaxDelay)
+	}
+
+	flushPending := func() {
+		if len(pending) == 0 {
+			return
+		}
+
+		var batch []*Event
+		for _, req := range pending {
+			batch = append(batch, req.events...)
+		}
+
+		start := time.Now()
+		err := q.store.writeBatch(batch)
+		atomic.AddInt64(&q.totalLatencyNs, int64(time.Since(start)))
+		atomic.AddInt64(&q.batchesFlushed, 1)
+		atomic.StoreInt64(&q.lastBatchSize, int64(len(batch)))
+		if err == nil {
+			atomic.AddInt64(&q.eventsWritten, int64(len(batch)))
+		}
+
+		for _, req := range pending {
+			req.done <- err
+			atomic.AddInt64(&q.queueDepth, -1)
+		}
+		pending = nil
+		pendingEvents = 0
+	}
+
+	for {
+		if timer == nil {
+			resetTimer()
+		}
+
+		select {
+		case req := <-q.requests:
+			pending = append(pending, req)
+			pendingEvents += len(req.events)
+			if pendingEvents >= q.opts.MaxBatchSize {
+				flushPending()
+				resetTimer()
+			}
+
+		case <-timer.C:
+			flushPending()
+			timer = nil
+
+		case ack := <-q.flushReq:
+			// Drain anything already queued before acking the flush.
+			for {
+				select {
+				case req := <-q.requests:
+					pending = append(pending, req)
+					pendingEvents += len(req.events)
+					continue
+				default:
+				}
+				break
+			}
+			flushPending()
+			ack <- nil
+
+		case <-q.done:
+			flushPending()
+			return
+		}
+	}
+}